@@ -25,18 +25,20 @@ func main() {
 	}
 	defer termbox.Close()
 
-	// Create a new label at (3,3) with dimensions 18x5
+	// Create a new label at (2,5) with dimensions 21x8, leaving 17x4
+	// for the text inside the border
 	lbl := termboxui.NewLabel()
-	lbl.Move(3, 6)
-	lbl.Resize(20, 5)
-	fmt.Fprint(lbl, "Use up/down arrow key to scroll!")
-	fmt.Fprintf(lbl, "Test Message!\nAB testing fox jumped over the fence!\n ")
+	lbl.Move(2, 5)
+	lbl.Resize(21, 8)
+	lbl.SetBorders(true)
+	lbl.Title = "Messages"
+	fmt.Fprintln(lbl, "Use up/down arrow key to scroll!")
+	fmt.Fprintf(lbl, "Test Message!\nAB testing fox jumped over the fence!\n \n")
 	fmt.Fprintln(lbl, "Moar messages! with moar line wrapping!")
 
 mainloop:
 	for {
 		lbl.Overwrite()
-		termboxui.DrawBox(2, 5, 20, 7)
 		termboxui.DrawVertLine(60, 3, 15)
 		termbox.Flush()
 		switch ev := termbox.PollEvent(); ev.Type {
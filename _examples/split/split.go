@@ -38,21 +38,47 @@ func main() {
 	split.Place(lbl)
 
 	lblInstr := termboxui.NewLabel()
-	fmt.Fprint(lblInstr, "Use Up/Down arrow keys and +/- keys to scroll!")
+	lblInstr.SetMarkup(true)
+	fmt.Fprint(lblInstr, "Use [::b]Up/Down[::-] arrow keys and [::b]+/-[::-] keys to scroll!")
 	lblInstr.SetFG(termbox.ColorGreen)
 	split.Place(lblInstr)
 
-	// Placed on the right
-	lbl2 := termboxui.NewLabel()
-	fmt.Fprintf(lbl2, string(b))
-	vsplit.Place(lbl2)
+	// Placed on the right, read from the file as it is scrolled
+	pager, err := termboxui.OpenPager("Mark.Twain-Tom.Sawyer.txt")
+	if err != nil {
+		panic(err)
+	}
+	defer pager.Close()
+	//the pager is drawn again as the file is indexed, without blocking
+	//the indexing once the event loop has exited
+	redraw := make(chan struct{}, 1)
+	pager.SetUpdateFunc(func() {
+		select {
+		case redraw <- struct{}{}:
+		default:
+		}
+	})
+	vsplit.Place(pager)
+
+	events := make(chan termbox.Event)
+	go func() {
+		for {
+			events <- termbox.PollEvent()
+		}
+	}()
 
 mainloop:
 	for {
 		termbox.Clear(termbox.ColorDefault, termbox.ColorDefault)
 		vsplit.Draw()
 		termbox.Flush()
-		switch ev := termbox.PollEvent(); ev.Type {
+		var ev termbox.Event
+		select {
+		case ev = <-events:
+		case <-redraw:
+			continue
+		}
+		switch ev.Type {
 		case termbox.EventKey:
 			switch ev.Key {
 			case termbox.KeyArrowUp:
@@ -66,9 +92,9 @@ mainloop:
 				case 'q':
 					break mainloop
 				case '+':
-					lbl2.NextPage()
+					pager.NextPage()
 				case '-':
-					lbl2.PrevPage()
+					pager.PrevPage()
 				}
 			}
 		case termbox.EventResize:
@@ -0,0 +1,157 @@
+package termboxui
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/nsf/termbox-go"
+)
+
+const esc = '\x1b'
+
+//decodeANSI removes the escape sequences from text, applying the SGR
+//(Select Graphic Rendition) sequences to the style starting from cur.
+//Every run of text between sequences is passed to emit along with its
+//style and emit returns the style to continue with.
+//The style at the end of text is returned along with any incomplete
+//sequence at the end of text so that it can be completed by following text.
+func decodeANSI(text string, cur Style, emit func(text string, style Style) Style) (Style, string) {
+	for len(text) > 0 {
+		i := strings.IndexByte(text, esc)
+		if i < 0 {
+			cur = emit(text, cur)
+			break
+		}
+		if i > 0 {
+			cur = emit(text[:i], cur)
+			text = text[i:]
+		}
+		n, complete := escapeLen(text)
+		if !complete {
+			return cur, text
+		}
+		if n > 2 && text[1] == '[' && text[n-1] == 'm' {
+			cur = applySGR(text[2:n-1], cur)
+		}
+		text = text[n:]
+	}
+	return cur, ""
+}
+
+//escapeLen returns the length of the escape sequence at the start of text.
+//complete is false if text ends before the sequence does.
+func escapeLen(text string) (n int, complete bool) {
+	if len(text) < 2 {
+		return len(text), false
+	}
+	switch text[1] {
+	case '[':
+		//CSI: parameter and intermediate bytes followed by a final byte
+		for i := 2; i < len(text); i++ {
+			if c := text[i]; c >= 0x40 && c <= 0x7e {
+				return i + 1, true
+			} else if c < 0x20 || c > 0x3f {
+				//malformed, drop what has been read so far
+				return i, true
+			}
+		}
+		return len(text), false
+	case ']', 'P', '_', '^':
+		//string sequences are terminated by BEL or ST (ESC \)
+		for i := 2; i < len(text); i++ {
+			if text[i] == '\a' {
+				return i + 1, true
+			}
+			if text[i] == esc {
+				if i+1 == len(text) {
+					return len(text), false
+				}
+				return i + 2, true
+			}
+		}
+		return len(text), false
+	}
+	//ESC followed by intermediate bytes and a final byte
+	for i := 1; i < len(text); i++ {
+		if c := text[i]; c < 0x20 || c > 0x2f {
+			return i + 1, true
+		}
+	}
+	return len(text), false
+}
+
+//sgrAttrs maps the SGR codes setting an attribute to the attribute.
+//Adding 20 to the code turns the attribute off again.
+var sgrAttrs = map[int]termbox.Attribute{
+	1: termbox.AttrBold,
+	2: termbox.AttrDim,
+	3: termbox.AttrCursive,
+	4: termbox.AttrUnderline,
+	5: termbox.AttrBlink,
+	7: termbox.AttrReverse,
+	8: termbox.AttrHidden,
+}
+
+//applySGR applies the ;-separated SGR parameters to cur.
+func applySGR(params string, cur Style) Style {
+	fields := strings.FieldsFunc(params, func(r rune) bool { return r == ';' || r == ':' })
+	if len(fields) == 0 {
+		return Style{}
+	}
+	codes := make([]int, len(fields))
+	for i, f := range fields {
+		codes[i], _ = strconv.Atoi(f)
+	}
+
+	fg, attrs, bg := cur.Fg&^attrMask, cur.Fg&attrMask, cur.Bg
+	for i := 0; i < len(codes); i++ {
+		code := codes[i]
+		switch {
+		case code == 0:
+			fg, attrs, bg = termbox.ColorDefault, 0, termbox.ColorDefault
+		case sgrAttrs[code] != 0:
+			attrs |= sgrAttrs[code]
+		case code == 22:
+			attrs &^= termbox.AttrBold | termbox.AttrDim
+		case code > 22 && code < 30 && sgrAttrs[code-20] != 0:
+			attrs &^= sgrAttrs[code-20]
+		case code >= 30 && code <= 37:
+			fg = termbox.ColorBlack + termbox.Attribute(code-30)
+		case code >= 90 && code <= 97:
+			fg = termbox.ColorDarkGray + termbox.Attribute(code-90)
+		case code >= 40 && code <= 47:
+			bg = termbox.ColorBlack + termbox.Attribute(code-40)
+		case code >= 100 && code <= 107:
+			bg = termbox.ColorDarkGray + termbox.Attribute(code-100)
+		case code == 39:
+			fg = termbox.ColorDefault
+		case code == 49:
+			bg = termbox.ColorDefault
+		case code == 38 || code == 48:
+			c, n := extendedColor(codes[i+1:])
+			i += n
+			if n == 0 {
+				continue
+			}
+			if code == 38 {
+				fg = c
+			} else {
+				bg = c
+			}
+		}
+	}
+	return Style{Fg: fg | attrs, Bg: bg}
+}
+
+//extendedColor parses the 5;n (256 colors) or 2;r;g;b (RGB) parameters
+//following a 38 or 48 code. It returns the color and the number of
+//parameters used or 0 if they are invalid.
+func extendedColor(codes []int) (termbox.Attribute, int) {
+	if len(codes) >= 2 && codes[0] == 5 {
+		return termbox.Attribute(codes[1]&0xff + 1), 2
+	}
+	if len(codes) >= 4 && codes[0] == 2 {
+		return termbox.RGBToAttribute(uint8(codes[1]), uint8(codes[2]), uint8(codes[3])), 4
+	}
+	return 0, 0
+}
@@ -0,0 +1,127 @@
+package termboxui
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/nsf/termbox-go"
+)
+
+//decodeWrites decodes the writes in order the same way as Label.Write,
+//carrying the style and any incomplete sequence over to the next write.
+func decodeWrites(writes []string) []Span {
+	var spans []Span
+	var cur Style
+	rest := ""
+	for _, w := range writes {
+		cur, rest = decodeANSI(rest+w, cur, func(text string, style Style) Style {
+			spans = append(spans, Span{Text: text, Style: style})
+			return style
+		})
+	}
+	return spans
+}
+
+func TestDecodeANSI(t *testing.T) {
+	red := Style{Fg: termbox.ColorRed}
+	bold := Style{Fg: termbox.AttrBold}
+	tests := []struct {
+		name   string
+		writes []string
+		want   []Span
+	}{
+		{"plain", []string{"hello"}, []Span{{"hello", Style{}}}},
+		{"color and reset", []string{"\x1b[31mred\x1b[0m plain"},
+			[]Span{{"red", red}, {" plain", Style{}}}},
+		{"empty reset", []string{"\x1b[31ma\x1b[mb"},
+			[]Span{{"a", red}, {"b", Style{}}}},
+		{"bright colors", []string{"\x1b[91;102mx"},
+			[]Span{{"x", Style{Fg: termbox.ColorLightRed, Bg: termbox.ColorDarkGray + 2}}}},
+		{"attributes off", []string{"\x1b[1;4mx\x1b[24my\x1b[22mz"},
+			[]Span{{"x", Style{Fg: termbox.AttrBold | termbox.AttrUnderline}}, {"y", bold}, {"z", Style{}}}},
+		{"default colors", []string{"\x1b[31;42mx\x1b[39;49my"},
+			[]Span{{"x", Style{Fg: termbox.ColorRed, Bg: termbox.ColorGreen}}, {"y", Style{}}}},
+		{"256 colors", []string{"\x1b[38;5;196mx"},
+			[]Span{{"x", Style{Fg: 197}}}},
+		{"rgb", []string{"\x1b[48;2;1;2;3mx"},
+			[]Span{{"x", Style{Bg: termbox.RGBToAttribute(1, 2, 3)}}}},
+		{"invalid extended color", []string{"\x1b[38;9mx"},
+			[]Span{{"x", Style{}}}},
+		{"other CSI dropped", []string{"a\x1b[2Kb"},
+			[]Span{{"a", Style{}}, {"b", Style{}}}},
+		{"OSC dropped", []string{"a\x1b]0;title\ab"},
+			[]Span{{"a", Style{}}, {"b", Style{}}}},
+
+		{"split after ESC", []string{"a\x1b", "[1mb"},
+			[]Span{{"a", Style{}}, {"b", bold}}},
+		{"split in parameters", []string{"\x1b[3", "1mred"},
+			[]Span{{"red", red}}},
+		{"split in extended color", []string{"\x1b[38;5", ";196mx"},
+			[]Span{{"x", Style{Fg: 197}}}},
+		{"split over three writes", []string{"\x1b", "[", "31mred"},
+			[]Span{{"red", red}}},
+		{"split before ST", []string{"a\x1b]0;t\x1b", "\\b"},
+			[]Span{{"a", Style{}}, {"b", Style{}}}},
+		{"style carried over", []string{"\x1b[31ma", "b"},
+			[]Span{{"a", red}, {"b", red}}},
+	}
+	for _, tt := range tests {
+		if got := decodeWrites(tt.writes); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLabelWriteSplitEscape(t *testing.T) {
+	lbl := NewLabel()
+	for _, w := range []string{"\x1b[3", "1mred\x1b", "[0m"} {
+		lbl.Write([]byte(w))
+	}
+	if got := lbl.Lines(); !reflect.DeepEqual(got, []string{"red"}) {
+		t.Fatalf("got lines %q", got)
+	}
+	if style, _ := lbl.content.at(0).styleAt(0, 0); style.Fg != termbox.ColorRed {
+		t.Errorf("got style %v, want red", style)
+	}
+}
+
+func TestLabelChunkedWrites(t *testing.T) {
+	lbl := NewLabel()
+	lbl.Write([]byte("hello wo"))
+	lbl.Write([]byte("rld\n"))
+	if got, want := lbl.Lines(), []string{"hello world"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	//the style of a line continued by the next write is kept
+	lbl.Write([]byte("\x1b[31mre"))
+	lbl.Write([]byte("d\x1b[0m plain\n"))
+	line := lbl.content.at(1)
+	if line.text != "red plain" {
+		t.Fatalf("got %q, want %q", line.text, "red plain")
+	}
+	if style, _ := line.styleAt(2, 0); style.Fg != termbox.ColorRed {
+		t.Errorf("got style %v at 2, want red", style)
+	}
+	if style, _ := line.styleAt(4, 0); style != (Style{}) {
+		t.Errorf("got style %v at 4, want the default", style)
+	}
+}
+
+func TestLabelOneByteWrites(t *testing.T) {
+	text := "\x1b[1mbold\x1b[0m line\r\nsecond\x1b]0;title\a line\nthird"
+	lbl := NewLabel()
+	if _, err := io.Copy(lbl, iotest.OneByteReader(strings.NewReader(text))); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"bold line", "second line", "third"}
+	if got := lbl.Lines(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if style, _ := lbl.content.at(0).styleAt(0, 0); style.Fg != termbox.AttrBold {
+		t.Errorf("got style %v, want bold", style)
+	}
+}
@@ -0,0 +1,43 @@
+package termboxui
+
+import (
+	"encoding/base64"
+	"io"
+
+	"github.com/nsf/termbox-go"
+)
+
+//Clipboard receives the text copied from a window.
+type Clipboard interface {
+	SetText(text string) error
+}
+
+//ClipboardFunc adapts a function to a Clipboard.
+type ClipboardFunc func(text string) error
+
+func (fn ClipboardFunc) SetText(text string) error { return fn(text) }
+
+//OSC52 copies text to the clipboard of the terminal with the OSC 52
+//escape sequence, which also works over ssh. Out is the terminal, such
+//as os.Stdout. The output buffered by termbox is flushed before writing
+//so that the sequence is not mixed into a frame being drawn.
+type OSC52 struct {
+	Out io.Writer
+}
+
+func (c OSC52) SetText(text string) error {
+	if c.Out == nil {
+		return ErrNoClipboard
+	}
+	if termbox.IsInit {
+		if err := termbox.Flush(); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(c.Out, "\x1b]52;c;"+base64.StdEncoding.EncodeToString([]byte(text))+"\a")
+	return err
+}
+
+//DefaultClipboard is used by the windows that have no clipboard set.
+//It is nil until set, for example to OSC52{Out: os.Stdout}.
+var DefaultClipboard Clipboard
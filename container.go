@@ -1,9 +1,6 @@
 package termboxui
 
 import (
-	"errors"
-	"log"
-
 	"github.com/nsf/termbox-go"
 )
 
@@ -31,16 +28,131 @@ type Container interface {
 	Remove(Window)
 	Move(x, y int)
 	Resize(width, height int)
+
+	//Children returns the slots of the container in order.
+	//Empty slots are nil.
+	Children() []Window
+	//IndexOf returns the slot holding the window or -1.
+	IndexOf(Window) int
+	InsertAt(i int, win Window) error
+	Replace(old, win Window) error
+	Swap(i, j int) error
+}
+
+//Parented is implemented by Windows that keep a reference
+//to the Container they have been placed in.
+//Containers update the reference whenever a window is
+//placed in or removed from them.
+type Parented interface {
+	Parent() Container
+	SetParent(Container)
+}
+
+//ParentOf returns the container win has been placed in.
+//It returns nil if win is not placed or does not implement Parented.
+func ParentOf(win Window) Container {
+	if p, ok := win.(Parented); ok {
+		return p.Parent()
+	}
+	return nil
+}
+
+//adopt sets c as the parent of win,
+//removing win from any other container it was placed in.
+func adopt(c Container, win Window) {
+	p, ok := win.(Parented)
+	if !ok {
+		return
+	}
+	if old := p.Parent(); old != nil && old != c {
+		old.Remove(win)
+	}
+	p.SetParent(c)
+}
+
+//release clears the parent of win.
+func release(win Window) {
+	if p, ok := win.(Parented); ok {
+		p.SetParent(nil)
+	}
+}
+
+//WalkFunc is called by Walk for every window in the tree.
+//Returning SkipChildren from a container skips its children.
+//Any other error stops the walk.
+type WalkFunc func(win Window) error
+
+//Walk visits root and then every window placed under it, depth first.
+//Empty slots are not visited.
+func Walk(root Window, fn WalkFunc) error {
+	err := fn(root)
+	if err == SkipChildren {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	c, ok := root.(Container)
+	if !ok {
+		return nil
+	}
+	for _, child := range c.Children() {
+		if child == nil {
+			continue
+		}
+		if err := Walk(child, fn); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 type Split interface {
-	Draw()
-	Place(Window) error
-	Remove(Window)
+	Container
 	RemoveFirst()
 	RemoveLast()
-	Move(x, y int)
-	Resize(w, h int)
+
+	Collapse(Window)
+	Hide(Window)
+	Maximize(Window)
+	Restore()
+	State() PaneState
+}
+
+//PaneState describes how a Split distributes its area between its two panes.
+type PaneState int
+
+const (
+	//PaneNormal places the divider at the split's location.
+	PaneNormal PaneState = iota
+	//PaneCollapsedFirst moves the divider to the left/top edge
+	//leaving the first pane with no space.
+	PaneCollapsedFirst
+	//PaneCollapsedLast moves the divider to the right/bottom edge
+	//leaving the last pane with no space.
+	PaneCollapsedLast
+	//PaneMaximizedFirst gives the first pane the entire area.
+	//The divider and the last pane are not drawn.
+	PaneMaximizedFirst
+	//PaneMaximizedLast gives the last pane the entire area.
+	//The divider and the first pane are not drawn.
+	PaneMaximizedLast
+	//PaneHiddenFirst hides the first pane, see Hide.
+	//It is laid out the same way as PaneMaximizedLast.
+	PaneHiddenFirst
+	//PaneHiddenLast hides the last pane, see Hide.
+	//It is laid out the same way as PaneMaximizedFirst.
+	PaneHiddenLast
+)
+
+//paneVisible returns whether the i'th pane is drawn in the given state.
+func paneVisible(state PaneState, i int) bool {
+	switch state {
+	case PaneCollapsedFirst, PaneMaximizedLast, PaneHiddenFirst:
+		return i == 1
+	case PaneCollapsedLast, PaneMaximizedFirst, PaneHiddenLast:
+		return i == 0
+	}
+	return true
 }
 
 //NewSplit creates a new horizontal split.
@@ -57,208 +169,438 @@ func NewSplit(location float32, sType SplitType) Split {
 	if location > -1 && location < 0 {
 		location = 1 + location
 	}
+	panes := splitPanes{x: 0, y: 0, width: w, height: h, location: location,
+		children: make([]Window, 2), bounds: make([]Rect, 2)}
 	if sType == SplitVertical {
-		return &VSplit{x: 0, y: 0, width: w, height: h, location: location, children: make([]Window, 2)}
+		s := &VSplit{splitPanes: panes}
+		s.owner = s
+		return s
 	} else {
-		return &HSplit{x: 0, y: 0, width: w, height: h, location: location, children: make([]Window, 2)}
+		s := &HSplit{splitPanes: panes}
+		s.owner = s
+		return s
 	}
 }
 
-//VSplit creates a vertical divider and tiles windows
-//next to the split.
-type VSplit struct {
+//splitLayout is implemented by the splits embedding splitPanes.
+type splitLayout interface {
+	Split
+	//paneRect returns the area of the i'th pane.
+	paneRect(i int) Rect
+}
+
+//splitPanes manages the two panes shared by VSplit and HSplit.
+//The geometry of each pane is left to the owner.
+type splitPanes struct {
+	Identity
+
 	x, y          int
 	width, height int
 
 	children []Window
+	//bounds is the area each child was last laid out in
+	bounds   []Rect
 	location float32
+	state    PaneState
+	//previous is the state Restore goes back to
+	previous PaneState
+	//hidden is set while the split itself is not being drawn
+	hidden bool
+
+	parent Container
+	owner  splitLayout
 }
 
-func (s *VSplit) Move(x, y int) {
+func (s *splitPanes) Parent() Container     { return s.parent }
+func (s *splitPanes) SetParent(c Container) { s.parent = c }
+
+func (s *splitPanes) Move(x, y int) {
 	s.x = x
 	s.y = y
+	s.layout()
+}
 
-	if s.children[0] != nil {
-		s.children[0].Move(x, y)
+func (s *splitPanes) Resize(w, h int) {
+	s.width = w
+	s.height = h
+	s.layout()
+}
+
+//OnShow notifies the children in visible panes that they are shown.
+func (s *splitPanes) OnShow() {
+	if !s.hidden {
+		return
 	}
-	if s.children[1] != nil {
-		s.children[1].Move(s.GetSplitLoc()+1, s.y)
+	s.hidden = false
+	for i, f := range s.children {
+		if f != nil && paneVisible(s.state, i) {
+			shown(f)
+		}
 	}
 }
 
-func (s *VSplit) Resize(w, h int) {
-	s.width = w
-	s.height = h
+//OnHide notifies the children in visible panes that they are hidden.
+func (s *splitPanes) OnHide() {
+	if s.hidden {
+		return
+	}
+	s.hidden = true
+	for i, f := range s.children {
+		if f != nil && paneVisible(s.state, i) {
+			hidden(f)
+		}
+	}
+}
 
-	splitx := s.GetSplitLoc()
+//layout moves and resizes both children to fit the current state.
+func (s *splitPanes) layout() {
+	for i, f := range s.children {
+		if f != nil {
+			s.layoutChild(i)
+		}
+	}
+}
+
+//layoutChild moves and resizes the i'th child into its pane.
+func (s *splitPanes) layoutChild(i int) {
+	r := s.owner.paneRect(i)
+	old := s.bounds[i]
+	s.bounds[i] = r
+	place(s.children[i], old, r)
+}
+
+//Children returns the first and last window. Empty panes are nil.
+func (s *splitPanes) Children() []Window {
+	return append([]Window(nil), s.children...)
+}
 
-	//Resize children
-	if s.children[0] != nil {
-		s.children[0].Resize(splitx-s.x, s.height)
+//IndexOf returns 0 for the first window, 1 for the last or
+//-1 if win is not in the split.
+func (s *splitPanes) IndexOf(win Window) int {
+	if win == nil {
+		return -1
 	}
+	for i, f := range s.children {
+		if f == win {
+			return i
+		}
+	}
+	return -1
+}
 
-	if s.children[1] != nil {
-		//need to move the second child since the location of split changed
-		s.children[1].Move(s.GetSplitLoc()+1, s.y)
-		s.children[1].Resize(s.x+s.width-splitx-1, s.height)
+//Place places the window in the first empty pane.
+//A window already in the split is moved out of its pane first.
+//If both panes have been taken, this will return ErrContainerFull.
+func (s *splitPanes) Place(win Window) error {
+	if win == nil {
+		return ErrNilWindow
 	}
+	s.Remove(win)
+	for i, f := range s.children {
+		if f == nil {
+			s.setChild(i, win)
+			return nil
+		}
+	}
+	return ErrContainerFull
 }
 
-//Place places the window either to the left or right of the split.
-//If both spaces are empty, it will be placed to the left.
-//If both spaces have been taken, this will return an error.
-func (s *VSplit) Place(win Window) error {
-	splitx := s.GetSplitLoc()
-	if s.children[0] == nil {
-		s.children[0] = win
-		win.Move(s.x, s.y)
-		win.Resize(splitx-s.x-1, s.height)
-	} else if s.children[1] == nil {
-		s.children[1] = win
-		win.Move(s.GetSplitLoc()+1, s.y)
-		win.Resize(s.x+s.width-splitx-1, s.height)
-	} else {
-		return errors.New("VSplit container is full")
+//InsertAt places the window in the i'th pane. If the pane is taken
+//its window is shifted into the other pane when that one is empty.
+func (s *splitPanes) InsertAt(i int, win Window) error {
+	if i < 0 || i >= len(s.children) {
+		return ErrIndexOutOfRange
+	}
+	if win == nil {
+		return ErrNilWindow
 	}
+	s.Remove(win)
+	if s.children[i] != nil {
+		other := 1 - i
+		if s.children[other] != nil {
+			return ErrContainerFull
+		}
+		s.moveChild(i, other)
+	}
+	s.setChild(i, win)
+	return nil
+}
+
+//Replace puts win in the pane occupied by old.
+//If win is in the other pane, that pane is left empty.
+func (s *splitPanes) Replace(old, win Window) error {
+	if win == nil {
+		return ErrNilWindow
+	}
+	i := s.IndexOf(old)
+	if i < 0 {
+		return ErrNotFound
+	}
+	if old == win {
+		return nil
+	}
+	s.Remove(win)
+	s.removeAt(i)
+	s.setChild(i, win)
 	return nil
 }
 
+//Swap exchanges the windows in the i'th and j'th panes.
+func (s *splitPanes) Swap(i, j int) error {
+	if i < 0 || i >= len(s.children) || j < 0 || j >= len(s.children) {
+		return ErrIndexOutOfRange
+	}
+	if i == j {
+		return nil
+	}
+	visible := s.visibility()
+	s.children[i], s.children[j] = s.children[j], s.children[i]
+	s.bounds[i], s.bounds[j] = s.bounds[j], s.bounds[i]
+	s.layout()
+	s.notifyVisibility(visible)
+	return nil
+}
+
+//moveChild moves the child in pane from into the empty pane to.
+func (s *splitPanes) moveChild(from, to int) {
+	visible := s.visibility()
+	s.children[to], s.children[from] = s.children[from], nil
+	s.bounds[to], s.bounds[from] = s.bounds[from], Rect{}
+	s.layoutChild(to)
+	s.notifyVisibility(visible)
+}
+
+func (s *splitPanes) setChild(i int, win Window) {
+	adopt(s.owner, win)
+	s.children[i] = win
+	s.bounds[i] = Rect{}
+	mounted(s.owner, win)
+	s.layoutChild(i)
+	if !s.hidden && paneVisible(s.state, i) {
+		shown(win)
+	} else {
+		placedHidden(win)
+	}
+}
+
 //Remove removes the window and makes its occupied space available again.
-func (s *VSplit) Remove(win Window) {
-	for i, f := range s.children {
-		if f == win {
-			s.children[i] = nil
-		}
+func (s *splitPanes) Remove(win Window) {
+	if i := s.IndexOf(win); i >= 0 {
+		s.removeAt(i)
 	}
 }
 
-//RemoveFirst removes the window to the left
-func (s *VSplit) RemoveFirst() {
-	s.children[0] = nil
+//RemoveFirst removes the window to the left/top
+func (s *splitPanes) RemoveFirst() {
+	s.removeAt(0)
 }
 
-//RemoveLast removes the window to the right
-func (s *VSplit) RemoveLast() {
-	s.children[1] = nil
+//RemoveLast removes the window to the right/bottom
+func (s *splitPanes) RemoveLast() {
+	s.removeAt(1)
 }
 
-//Gets the location of the split relative to the entire screen.
-func (s *VSplit) GetSplitLoc() int {
-	if s.location > 0 && s.location < 1 {
-		return s.x + int(float32(s.width)*s.location)
+func (s *splitPanes) removeAt(i int) {
+	if s.children[i] == nil {
+		return
+	}
+	win := s.children[i]
+	if !s.hidden && paneVisible(s.state, i) {
+		hidden(win)
 	}
-	return s.x + (s.width+int(s.location))%s.width
+	s.children[i] = nil
+	s.bounds[i] = Rect{}
+	release(win)
+	unmounted(s.owner, win)
+	s.layout()
 }
 
-//Draw draws the split and its children
-func (s *VSplit) Draw() {
-	DrawVertLine(s.GetSplitLoc(), s.y, s.height)
-	for _, f := range s.children {
-		if f != nil {
-			f.Draw()
-		}
+//Collapse moves the divider to the edge of win's side leaving
+//the other window with all of the space except the divider.
+func (s *splitPanes) Collapse(win Window) {
+	switch s.IndexOf(win) {
+	case 0:
+		s.setState(PaneCollapsedFirst)
+	case 1:
+		s.setState(PaneCollapsedLast)
 	}
 }
 
-//HSplit creates a vertical divider and tiles windows
-//next to the split.
-type HSplit struct {
-	x, y          int
-	width, height int
+//Hide temporarily hides win and lets the other window
+//take up the entire area of the split.
+func (s *splitPanes) Hide(win Window) {
+	switch s.IndexOf(win) {
+	case 0:
+		s.setState(PaneHiddenFirst)
+	case 1:
+		s.setState(PaneHiddenLast)
+	}
+}
 
-	children []Window
-	location float32
+//Maximize lets win take up the entire area of the split.
+func (s *splitPanes) Maximize(win Window) {
+	switch s.IndexOf(win) {
+	case 0:
+		s.setState(PaneMaximizedFirst)
+	case 1:
+		s.setState(PaneMaximizedLast)
+	}
 }
 
-func (s *HSplit) Move(x, y int) {
-	s.x = x
-	s.y = y
+//Restore undoes the last Collapse, Hide or Maximize, going back to the
+//state before it. Restoring again moves the divider back to its location.
+func (s *splitPanes) Restore() {
+	previous := s.previous
+	s.setState(previous)
+	s.previous = PaneNormal
+}
 
-	s.moveChild(3)
+//State returns how the panes are currently laid out.
+func (s *splitPanes) State() PaneState {
+	return s.state
 }
 
-func (s *HSplit) Resize(w, h int) {
-	s.width = w
-	s.height = h
+func (s *splitPanes) setState(state PaneState) {
+	if state == s.state {
+		return
+	}
+	visible := s.visibility()
+	s.previous, s.state = s.state, state
+	s.layout()
+	s.notifyVisibility(visible)
+}
 
-	//Resize children
-	s.resizeChild(3)
+//visibility returns which children are currently being drawn.
+func (s *splitPanes) visibility() map[Window]bool {
+	visible := make(map[Window]bool, len(s.children))
+	for i, f := range s.children {
+		if f != nil && !s.hidden && paneVisible(s.state, i) {
+			visible[f] = true
+		}
+	}
+	return visible
 }
 
-//moves child into the correct location.
-//1 is first child
-//2 is second child
-//3 is both
-func (s *HSplit) moveChild(c int) {
-	if c&1 != 0 && s.children[0] != nil {
-		s.children[0].Move(s.x, s.y)
+//notifyVisibility calls OnShow or OnHide on the children whose
+//visibility differs from before.
+func (s *splitPanes) notifyVisibility(before map[Window]bool) {
+	after := s.visibility()
+	for _, f := range s.children {
+		if f == nil || before[f] == after[f] {
+			continue
+		}
+		if after[f] {
+			shown(f)
+		} else {
+			hidden(f)
+		}
 	}
-	if c&2 != 0 && s.children[1] != nil {
-		s.children[1].Move(s.x, s.GetSplitLoc()+1)
+}
+
+//maximized returns whether one pane takes up the entire area.
+func (s *splitPanes) maximized() bool {
+	switch s.state {
+	case PaneMaximizedFirst, PaneMaximizedLast, PaneHiddenFirst, PaneHiddenLast:
+		return true
 	}
+	return false
 }
-func (s *HSplit) resizeChild(c int) {
-	splity := s.GetSplitLoc()
-	if c&1 != 0 && s.children[0] != nil {
-		s.children[0].Resize(s.width, splity-s.y)
-	}
-	if c&2 != 0 && s.children[1] != nil {
-		s.children[1].Move(s.x, s.GetSplitLoc()+1)
-		s.children[1].Resize(s.width, s.y+s.height-splity-1)
-	}
-}
-
-//Place places the window either to the left or right of the split.
-//If both spaces are empty, it will be placed to the left.
-//If both spaces have been taken, this will return an error.
-func (s *HSplit) Place(win Window) error {
-	if s.children[0] == nil {
-		s.children[0] = win
-		s.moveChild(1)
-		s.resizeChild(1)
-	} else if s.children[1] == nil {
-		s.children[1] = win
-		s.resizeChild(2) //resizing second child moves and resizes
-	} else {
-		log.Print("container full")
-		return errors.New("HSplit container is full")
+
+//splitLoc gets the location of the split along an axis
+//starting at start with the given length.
+//A collapsed split is located on the edge of the collapsed side.
+func (s *splitPanes) splitLoc(start, length int) int {
+	switch s.state {
+	case PaneCollapsedFirst:
+		return start
+	case PaneCollapsedLast:
+		return start + length - 1
 	}
-	return nil
+	if s.location > 0 && s.location < 1 {
+		return start + int(float32(length)*s.location)
+	}
+	return start + (length+int(s.location))%length
 }
 
-//Remove removes the window and makes its occupied space available again.
-func (s *HSplit) Remove(win Window) {
+//drawChildren draws the children in visible panes.
+func (s *splitPanes) drawChildren() {
 	for i, f := range s.children {
-		if f == win {
-			s.children[i] = nil
+		if f != nil && paneVisible(s.state, i) {
+			f.Draw()
 		}
 	}
 }
 
-//RemoveFirst removes the window to the left
-func (s *HSplit) RemoveFirst() {
-	s.children[0] = nil
+//VSplit creates a vertical divider and tiles windows
+//next to the split.
+type VSplit struct {
+	splitPanes
 }
 
-//RemoveLast removes the window to the right
-func (s *HSplit) RemoveLast() {
-	s.children[1] = nil
+func (s *VSplit) paneRect(i int) Rect {
+	x, w := s.x, s.width
+	splitx := s.GetSplitLoc()
+	if s.maximized() {
+		//the maximized child keeps the entire area
+		if !paneVisible(s.state, i) {
+			w = 0
+		}
+	} else if i == 0 {
+		w = splitx - s.x
+	} else {
+		x = splitx + 1
+		w = s.x + s.width - splitx - 1
+	}
+	return Rect{X: x, Y: s.y, Width: w, Height: s.height}
 }
 
 //Gets the location of the split relative to the entire screen.
-func (s *HSplit) GetSplitLoc() int {
-	if s.location > 0 && s.location < 1 {
-		return s.y + int(float32(s.height)*s.location)
+//A collapsed split is located on the edge of the collapsed side.
+func (s *VSplit) GetSplitLoc() int {
+	return s.splitLoc(s.x, s.width)
+}
+
+//Draw draws the split and its children
+func (s *VSplit) Draw() {
+	if !s.maximized() {
+		DrawVertLine(s.GetSplitLoc(), s.y, s.height)
 	}
-	return s.y + (s.height+int(s.location))%s.height
+	s.drawChildren()
+}
+
+//HSplit creates a horizontal divider and tiles windows
+//above and below the split.
+type HSplit struct {
+	splitPanes
+}
+
+func (s *HSplit) paneRect(i int) Rect {
+	y, h := s.y, s.height
+	splity := s.GetSplitLoc()
+	if s.maximized() {
+		//the maximized child keeps the entire area
+		if !paneVisible(s.state, i) {
+			h = 0
+		}
+	} else if i == 0 {
+		h = splity - s.y
+	} else {
+		y = splity + 1
+		h = s.y + s.height - splity - 1
+	}
+	return Rect{X: s.x, Y: y, Width: s.width, Height: h}
+}
+
+//Gets the location of the split relative to the entire screen.
+//A collapsed split is located on the edge of the collapsed side.
+func (s *HSplit) GetSplitLoc() int {
+	return s.splitLoc(s.y, s.height)
 }
 
 //Draw draws the split and its children
 func (s *HSplit) Draw() {
-	DrawHorzLine(s.x, s.GetSplitLoc(), s.width)
-	for _, f := range s.children {
-		if f != nil {
-			f.Draw()
-		}
+	if !s.maximized() {
+		DrawHorzLine(s.x, s.GetSplitLoc(), s.width)
 	}
+	s.drawChildren()
 }
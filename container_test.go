@@ -0,0 +1,112 @@
+package termboxui
+
+import "testing"
+
+func TestSplitPlaceTwice(t *testing.T) {
+	s := NewSplit(0.5, SplitVertical)
+	a, b := NewLabel(), NewLabel()
+	s.Place(a)
+	if err := s.Place(a); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Children(); got[0] != a || got[1] != nil {
+		t.Errorf("got children %v, want a in the first pane only", got)
+	}
+	if err := s.Place(b); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Place(b); err != nil {
+		t.Errorf("placing b again: %v", err)
+	}
+	if got := s.Children(); got[0] != a || got[1] != b {
+		t.Errorf("got children %v, want a and b", got)
+	}
+}
+
+func TestSplitMoveWithin(t *testing.T) {
+	s := NewSplit(0.5, SplitHorizontal)
+	a, b := NewLabel(), NewLabel()
+	s.Place(a)
+	if err := s.InsertAt(1, a); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Children(); got[0] != nil || got[1] != a {
+		t.Errorf("InsertAt: got children %v, want a in the last pane only", got)
+	}
+
+	s.Place(b)
+	if err := s.Replace(b, a); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Children(); got[0] != a || got[1] != nil {
+		t.Errorf("Replace: got children %v, want a in the first pane only", got)
+	}
+	if b.Parent() != nil {
+		t.Error("the replaced window kept its parent")
+	}
+	if err := s.Replace(a, a); err != nil || s.IndexOf(a) != 0 {
+		t.Errorf("Replace with itself: got %v at %d", err, s.IndexOf(a))
+	}
+}
+
+func TestPlaceNil(t *testing.T) {
+	s := NewSplit(0.5, SplitVertical)
+	a := NewLabel()
+	s.Place(a)
+	if err := s.Place(nil); err != ErrNilWindow {
+		t.Errorf("Place: got %v, want ErrNilWindow", err)
+	}
+	if err := s.InsertAt(1, nil); err != ErrNilWindow {
+		t.Errorf("InsertAt: got %v, want ErrNilWindow", err)
+	}
+	if err := s.Replace(a, nil); err != ErrNilWindow || s.IndexOf(a) != 0 {
+		t.Errorf("Replace: got %v, want ErrNilWindow with a kept", err)
+	}
+
+	f := NewFrame()
+	f.Place(a)
+	if err := f.Replace(a, nil); err != ErrNilWindow || f.IndexOf(a) != 0 {
+		t.Errorf("Frame.Replace: got %v, want ErrNilWindow with a kept", err)
+	}
+	if err := f.Place(a); err != nil || a.Parent() != f {
+		t.Errorf("Frame.Place again: got %v with parent %v", err, a.Parent())
+	}
+}
+
+func TestSplitStates(t *testing.T) {
+	s := NewSplit(0.5, SplitVertical)
+	s.Resize(20, 10)
+	a, b := NewLabel(), NewLabel()
+	s.Place(a)
+	s.Place(b)
+
+	s.Hide(a)
+	if got := s.State(); got != PaneHiddenFirst {
+		t.Errorf("Hide: got state %v, want PaneHiddenFirst", got)
+	}
+	if w, _ := b.Size(); w != 20 {
+		t.Errorf("Hide: the other pane got width %d, want 20", w)
+	}
+	s.Restore()
+	if got := s.State(); got != PaneNormal {
+		t.Errorf("Restore after Hide: got state %v, want PaneNormal", got)
+	}
+
+	steps := []struct {
+		name string
+		do   func()
+		want PaneState
+	}{
+		{"Collapse", func() { s.Collapse(a) }, PaneCollapsedFirst},
+		{"Maximize", func() { s.Maximize(b) }, PaneMaximizedLast},
+		{"Restore", s.Restore, PaneCollapsedFirst},
+		{"Restore again", s.Restore, PaneNormal},
+		{"Restore when normal", s.Restore, PaneNormal},
+	}
+	for _, step := range steps {
+		step.do()
+		if got := s.State(); got != step.want {
+			t.Errorf("%s: got state %v, want %v", step.name, got, step.want)
+		}
+	}
+}
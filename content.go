@@ -0,0 +1,116 @@
+package termboxui
+
+import "strings"
+
+//SetText replaces all of the text with text, written the same way as by Write.
+func (lbl *Label) SetText(text string) {
+	lbl.Clear()
+	lbl.Write([]byte(text))
+}
+
+//Lines returns the text of every line, without styles.
+func (lbl *Label) Lines() []string {
+	lines := make([]string, lbl.content.len())
+	for i := range lines {
+		lines[i] = lbl.content.at(i).text
+	}
+	return lines
+}
+
+//LineCount returns the number of lines of text.
+func (lbl *Label) LineCount() int {
+	return lbl.content.len()
+}
+
+//InsertLines inserts the lines before the i'th line, or after the last
+//line if i is LineCount. Each line is parsed the same way as by Write,
+//starting from the default style, and is split on newlines.
+func (lbl *Label) InsertLines(i int, lines ...string) error {
+	if i < 0 || i > lbl.content.len() {
+		return ErrIndexOutOfRange
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	lbl.splice(i, 0, lbl.parse(strings.Join(lines, "\n")))
+	return nil
+}
+
+//ReplaceLine replaces the text of the i'th line, keeping the scroll
+//position, its sign and its alignment. The text is parsed the same way
+//as by InsertLines. If it spans several lines the first one takes the
+//place of the line.
+func (lbl *Label) ReplaceLine(i int, text string) error {
+	if i < 0 || i >= lbl.content.len() {
+		return ErrIndexOutOfRange
+	}
+	lbl.format()
+	lines := lbl.parse(text)
+	if len(lines) > 0 {
+		old := lbl.content.at(i)
+		lines[0].align, lines[0].sign, lines[0].prefix = old.align, old.sign, old.prefix
+	}
+	lbl.splice(i, 1, lines)
+	return nil
+}
+
+//DeleteLines removes n lines starting from the i'th line.
+func (lbl *Label) DeleteLines(i, n int) error {
+	if i < 0 || n < 0 || i+n > lbl.content.len() {
+		return ErrIndexOutOfRange
+	}
+	lbl.splice(i, n, nil)
+	return nil
+}
+
+//Truncate removes the lines after the first n lines.
+func (lbl *Label) Truncate(n int) {
+	if n < 0 {
+		n = 0
+	}
+	if n < lbl.content.len() {
+		lbl.splice(n, lbl.content.len()-n, nil)
+	}
+}
+
+//parse turns text into lines the same way as Write without
+//changing the style carried over between writes.
+func (lbl *Label) parse(text string) []styledLine {
+	spans, _, _ := lbl.decode(text, Style{})
+	return spansToLines(spans)
+}
+
+//splice replaces the n lines from the i'th line with lines, keeping the
+//scroll position, the search, the filter and the highlighting in step.
+func (lbl *Label) splice(i, n int, lines []styledLine) {
+	//apply the lines dropped so far so that i refers to the same lines
+	lbl.format()
+
+	m := len(lines)
+	if i+n >= lbl.content.len() {
+		//lines written later start after the lines spliced in
+		lbl.open = false
+	}
+	lbl.content.splice(i, n, lines...)
+	if lbl.searching() {
+		lbl.search.splice(&lbl.content, i, n, m)
+	}
+	if lbl.filter != nil {
+		lbl.filter.splice(&lbl.content, i, n, m)
+	}
+	if lbl.highlighter != nil {
+		lbl.rehighlight(i, n, m)
+	}
+	if lbl.sel != nil && !lbl.sel.splice(i, n, m) {
+		lbl.sel = nil
+	}
+
+	if lbl.startLine >= i+n {
+		lbl.startLine += m - n
+	} else if lbl.startLine >= i {
+		lbl.startLine, lbl.startRow = i, 0
+	}
+	//inserted lines may take the content past the limit
+	lbl.dropped += lbl.content.trim()
+	lbl.changed = true
+}
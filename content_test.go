@@ -0,0 +1,158 @@
+package termboxui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newContentLabel(lines ...string) *Label {
+	lbl := NewLabel()
+	lbl.Resize(20, 3)
+	lbl.SetText(strings.Join(lines, "\n"))
+	return lbl
+}
+
+func TestLabelEdits(t *testing.T) {
+	lbl := newContentLabel("a", "b", "c")
+	steps := []struct {
+		name string
+		edit func() error
+		want []string
+	}{
+		{"insert nothing", func() error { return lbl.InsertLines(1) }, []string{"a", "b", "c"}},
+		{"insert", func() error { return lbl.InsertLines(1, "x", "y\nz") }, []string{"a", "x", "y", "z", "b", "c"}},
+		{"insert at end", func() error { return lbl.InsertLines(6, "d") }, []string{"a", "x", "y", "z", "b", "c", "d"}},
+		{"replace", func() error { return lbl.ReplaceLine(0, "A") }, []string{"A", "x", "y", "z", "b", "c", "d"}},
+		{"delete", func() error { return lbl.DeleteLines(1, 3) }, []string{"A", "b", "c", "d"}},
+		{"truncate", func() error { lbl.Truncate(2); return nil }, []string{"A", "b"}},
+	}
+	for _, step := range steps {
+		if err := step.edit(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if got := lbl.Lines(); !reflect.DeepEqual(got, step.want) {
+			t.Fatalf("%s: got %q, want %q", step.name, got, step.want)
+		}
+	}
+
+	for _, err := range []error{
+		lbl.InsertLines(3, "x"),
+		lbl.ReplaceLine(2, "x"),
+		lbl.DeleteLines(1, 2),
+		lbl.DeleteLines(-1, 1),
+	} {
+		if err != ErrIndexOutOfRange {
+			t.Errorf("got %v, want ErrIndexOutOfRange", err)
+		}
+	}
+}
+
+func TestLabelEditsSearch(t *testing.T) {
+	lbl := newContentLabel("a x", "b", "c x", "d")
+	lbl.Search("x", SearchOptions{})
+	lbl.NextMatch()
+	if got := lbl.CurrentMatch(); got != 1 {
+		t.Fatalf("got match %d, want 1", got)
+	}
+
+	steps := []struct {
+		name           string
+		edit           func()
+		count, current int
+	}{
+		//the current match stays on the same text
+		{"insert before", func() { lbl.InsertLines(0, "x x") }, 4, 3},
+		{"insert after", func() { lbl.InsertLines(4, "x") }, 5, 3},
+		{"replace before", func() { lbl.ReplaceLine(0, "none") }, 3, 1},
+		{"replace other", func() { lbl.ReplaceLine(2, "x b x") }, 5, 3},
+		{"delete before", func() { lbl.DeleteLines(0, 1) }, 5, 3},
+		//the current match goes away with its line
+		{"replace current", func() { lbl.ReplaceLine(2, "c x") }, 5, -1},
+		{"truncate", func() { lbl.Truncate(1) }, 1, -1},
+	}
+	for _, step := range steps {
+		step.edit()
+		if got := lbl.MatchCount(); got != step.count {
+			t.Errorf("%s: got %d matches, want %d", step.name, got, step.count)
+		}
+		if got := lbl.CurrentMatch(); got != step.current {
+			t.Errorf("%s: got match %d, want %d", step.name, got, step.current)
+		}
+	}
+}
+
+func TestLabelEditsSelection(t *testing.T) {
+	lbl := newContentLabel("aa", "bb", "cc", "dd")
+	lbl.Select(TextPos{1, 0}, TextPos{2, 1})
+
+	steps := []struct {
+		name string
+		edit func()
+		want string
+	}{
+		{"insert before", func() { lbl.InsertLines(0, "zz") }, "bb\nc"},
+		{"replace after", func() { lbl.ReplaceLine(4, "x") }, "bb\nc"},
+		{"delete before", func() { lbl.DeleteLines(0, 1) }, "bb\nc"},
+		{"insert after", func() { lbl.InsertLines(3, "yy") }, "bb\nc"},
+		{"replace selected", func() { lbl.ReplaceLine(1, "x") }, ""},
+	}
+	for _, step := range steps {
+		step.edit()
+		if got := lbl.SelectedText(); got != step.want {
+			t.Errorf("%s: got %q, want %q", step.name, got, step.want)
+		}
+	}
+}
+
+func TestLabelEditsHighlight(t *testing.T) {
+	lbl := newContentLabel("a", "/* start", "b", "end */", "c")
+	lbl.SetHighlighter(GoHighlighter{})
+	comment := DefaultHighlightStyles.Comment
+	inComment := func(i int) bool {
+		lbl.format()
+		line := lbl.content.at(i)
+		return len(line.highlit) == 1 && line.highlit[0].style == comment
+	}
+	if !inComment(2) {
+		t.Fatal("b is not highlighted as a comment")
+	}
+
+	//closing the comment early highlights the lines after it again
+	lbl.ReplaceLine(1, "/* start */")
+	if inComment(2) {
+		t.Error("b is still highlighted as a comment")
+	}
+	lbl.InsertLines(2, "/*")
+	if !inComment(3) {
+		t.Error("b is not highlighted as a comment after reopening it")
+	}
+	lbl.DeleteLines(2, 1)
+	if inComment(2) {
+		t.Error("b is still highlighted as a comment after deleting the opening line")
+	}
+}
+
+func TestLabelEditsScroll(t *testing.T) {
+	lbl := newContentLabel("0", "1", "2", "3", "4", "5", "6", "7", "8", "9")
+	lbl.ScrollTo(5)
+	top := func() string {
+		lbl.format()
+		return lbl.content.at(lbl.startLine).text
+	}
+	if got := top(); got != "5" {
+		t.Fatalf("got %q at the top, want 5", got)
+	}
+	lbl.InsertLines(0, "x", "y")
+	if got := top(); got != "5" {
+		t.Errorf("insert: got %q at the top, want 5", got)
+	}
+	lbl.DeleteLines(0, 4)
+	if got := top(); got != "5" {
+		t.Errorf("delete: got %q at the top, want 5", got)
+	}
+	lbl.ReplaceLine(3, "five")
+	if got := top(); got != "five" {
+		t.Errorf("replace: got %q at the top, want five", got)
+	}
+}
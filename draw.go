@@ -36,21 +36,75 @@ func DrawHorzLine(x, y int, w int) {
 
 //Draws a box along the perimeter of the rectangular area
 func DrawBox(x, y, w, h int) {
+	drawBox(x, y, w, h, Style{})
+}
+
+//drawBox draws a box the same way as DrawBox using the style.
+func drawBox(x, y, w, h int, style Style) {
 	//Draw the top and bottom
 	for i := x + 1; i < x+w; i++ {
-		termbox.SetCell(i, y, '─', termbox.ColorDefault, termbox.ColorDefault)
-		termbox.SetCell(i, y+h, '─', termbox.ColorDefault, termbox.ColorDefault)
+		termbox.SetCell(i, y, '─', style.Fg, style.Bg)
+		termbox.SetCell(i, y+h, '─', style.Fg, style.Bg)
 	}
 
 	//Draw the sides
 	for i := y + 1; i < y+h; i++ {
-		termbox.SetCell(x, i, '│', termbox.ColorDefault, termbox.ColorDefault)
-		termbox.SetCell(x+w, i, '│', termbox.ColorDefault, termbox.ColorDefault)
+		termbox.SetCell(x, i, '│', style.Fg, style.Bg)
+		termbox.SetCell(x+w, i, '│', style.Fg, style.Bg)
 	}
 
 	//Draw the cornors
-	termbox.SetCell(x, y, '┌', termbox.ColorDefault, termbox.ColorDefault)
-	termbox.SetCell(x, y+h, '└', termbox.ColorDefault, termbox.ColorDefault)
-	termbox.SetCell(x+w, y, '┐', termbox.ColorDefault, termbox.ColorDefault)
-	termbox.SetCell(x+w, y+h, '┘', termbox.ColorDefault, termbox.ColorDefault)
+	termbox.SetCell(x, y, '┌', style.Fg, style.Bg)
+	termbox.SetCell(x, y+h, '└', style.Fg, style.Bg)
+	termbox.SetCell(x+w, y, '┐', style.Fg, style.Bg)
+	termbox.SetCell(x+w, y+h, '┘', style.Fg, style.Bg)
+}
+
+//cellWriter draws the cells of a row left to right, clipping those
+//left of the row or past its width.
+type cellWriter struct {
+	x, y  int
+	width int
+	//col is the column of the next cell, negative while
+	//left of the row
+	col int
+}
+
+//put draws ch over width cells. A wide character only partly in
+//the row is drawn as blank cells.
+func (w *cellWriter) put(ch rune, width int, style Style) {
+	if w.col >= 0 && w.col+width <= w.width {
+		//termbox draws a wide character over the cell after it
+		termbox.SetCell(w.x+w.col, w.y, ch, style.Fg, style.Bg)
+	} else if w.col+width > 0 && w.col < w.width {
+		for c := w.col; c < w.col+width; c++ {
+			if c >= 0 && c < w.width {
+				termbox.SetCell(w.x+c, w.y, ' ', style.Fg, style.Bg)
+			}
+		}
+	}
+	w.col += width
+}
+
+//putText draws a character of text over the width found by cellWidth:
+//tabs as blank cells and control characters as ^X.
+func (w *cellWriter) putText(ch rune, width int, style Style) {
+	switch {
+	case ch == '\t':
+		for i := 0; i < width; i++ {
+			w.put(' ', 1, style)
+		}
+	case isControl(ch):
+		w.put('^', 1, style)
+		w.put(caret(ch), 1, style)
+	default:
+		w.put(ch, width, style)
+	}
+}
+
+//fill draws blank cells up to the end of the row.
+func (w *cellWriter) fill(style Style) {
+	for w.col < w.width {
+		w.put(' ', 1, style)
+	}
 }
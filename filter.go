@@ -0,0 +1,108 @@
+package termboxui
+
+//filter hides the lines of a Label that do not match,
+//apart from the lines around the matching ones.
+type filter struct {
+	match   func(text string) bool
+	context int
+	//matched is set for every line of content that matches
+	matched []bool
+}
+
+//update matches the lines added to content since the last update after
+//forgetting the dropped lines.
+func (f *filter) update(content *lineBuffer, dropped int) {
+	if dropped > len(f.matched) {
+		dropped = len(f.matched)
+	}
+	f.matched = f.matched[dropped:]
+	for i := len(f.matched); i < content.len(); i++ {
+		f.matched = append(f.matched, f.match(content.at(i).text))
+	}
+}
+
+//splice updates the lines matched once the n lines from the i'th line
+//have been replaced with m lines.
+func (f *filter) splice(content *lineBuffer, i, n, m int) {
+	if len(f.matched) <= i {
+		//the lines have not been matched yet
+		return
+	}
+	if n == m && i+n <= len(f.matched) {
+		for j := i; j < i+m; j++ {
+			f.matched[j] = f.match(content.at(j).text)
+		}
+		return
+	}
+	var tail []bool
+	if i+n < len(f.matched) {
+		tail = f.matched[i+n:]
+	}
+	matched := append([]bool(nil), f.matched[:i]...)
+	for j := i; j < i+m; j++ {
+		matched = append(matched, f.match(content.at(j).text))
+	}
+	f.matched = append(matched, tail...)
+}
+
+//shown returns whether the i'th line matches or is
+//within context lines of a line that matches.
+func (f *filter) shown(i int) bool {
+	if i >= len(f.matched) {
+		//not matched yet
+		return true
+	}
+	for j := i - f.context; j <= i+f.context; j++ {
+		if j >= 0 && j < len(f.matched) && f.matched[j] {
+			return true
+		}
+	}
+	return false
+}
+
+//SetFilter shows only the lines for which match returns true along with
+//context lines before and after each of them. Text written later is
+//filtered as well. The scroll position is kept on the same line of text
+//so that clearing the filter shows the line at the top of the view
+//in its full context. A nil match clears the filter.
+func (lbl *Label) SetFilter(match func(text string) bool, context int) {
+	if match == nil {
+		lbl.ClearFilter()
+		return
+	}
+	if context < 0 {
+		context = 0
+	}
+	lbl.filter = &filter{match: match, context: context}
+	lbl.filter.update(&lbl.content, 0)
+	lbl.changed = true
+}
+
+//SetFilterPattern filters the lines with a regexp or plain text the same
+//way as Search matches it. An empty pattern clears the filter.
+//It returns an error if pattern is not a valid regular expression.
+func (lbl *Label) SetFilterPattern(pattern string, opts SearchOptions, context int) error {
+	if pattern == "" {
+		lbl.ClearFilter()
+		return nil
+	}
+	re, err := compileSearch(pattern, opts)
+	if err != nil {
+		return err
+	}
+	lbl.SetFilter(re.MatchString, context)
+	return nil
+}
+
+//ClearFilter shows every line again.
+func (lbl *Label) ClearFilter() {
+	if lbl.filter != nil {
+		lbl.filter = nil
+		lbl.changed = true
+	}
+}
+
+//Filtering returns whether a filter is set.
+func (lbl *Label) Filtering() bool {
+	return lbl.filter != nil
+}
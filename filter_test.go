@@ -0,0 +1,60 @@
+package termboxui
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+//shownLines returns the indices of the lines the filter leaves shown.
+func shownLines(lbl *Label) []int {
+	lbl.format()
+	var lines []int
+	for i := 0; i < lbl.content.len(); i++ {
+		if len(lbl.rows(i)) > 0 {
+			lines = append(lines, i)
+		}
+	}
+	return lines
+}
+
+func TestLabelFilterContext(t *testing.T) {
+	lbl := NewLabel()
+	lbl.Resize(20, 5)
+	for i := 0; i < 20; i++ {
+		fmt.Fprintln(lbl, "line", i)
+	}
+	lbl.SetFilter(func(text string) bool { return strings.HasSuffix(text, "7") }, 1)
+	if got, want := shownLines(lbl), []int{6, 7, 8, 16, 17, 18}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got lines %v, want %v", got, want)
+	}
+
+	//lines written later are filtered as well
+	fmt.Fprintln(lbl, "line 27")
+	if got, want := shownLines(lbl), []int{6, 7, 8, 16, 17, 18, 19, 20}; !reflect.DeepEqual(got, want) {
+		t.Errorf("after a write got lines %v, want %v", got, want)
+	}
+}
+
+func TestLabelClearFilterKeepsPosition(t *testing.T) {
+	lbl := NewLabel()
+	lbl.Resize(20, 1)
+	for i := 0; i < 20; i++ {
+		fmt.Fprintln(lbl, "line", i)
+	}
+	lbl.ScrollTo(7)
+	lbl.SetFilterPattern("7", SearchOptions{}, 0)
+	if offset, _, total := lbl.ScrollState(); offset != 0 || total != 2 {
+		t.Errorf("filtered: got offset %d of %d, want 0 of 2", offset, total)
+	}
+	lbl.Scroll(1)
+
+	lbl.ClearFilter()
+	if offset, _, _ := lbl.ScrollState(); offset != 17 {
+		t.Errorf("cleared: got offset %d, want 17", offset)
+	}
+	if got := lbl.content.at(lbl.startLine).text; got != "line 17" {
+		t.Errorf("cleared: got %q at the top, want %q", got, "line 17")
+	}
+}
@@ -4,30 +4,138 @@ func NewFrame() *Frame {
 	return &Frame{x: 0, y: 0}
 }
 
+//Frame is a container holding a single window
+//which takes up the entire area of the frame.
 type Frame struct {
+	Identity
+
 	x, y          int
 	width, height int
 
-	child Window
+	child  Window
+	bounds Rect
+	hidden bool
+	parent Container
 }
 
+func (f *Frame) Parent() Container     { return f.parent }
+func (f *Frame) SetParent(c Container) { f.parent = c }
+
 func (f *Frame) Move(x, y int) {
 	f.x = x
 	f.y = y
-	if f.child != nil {
-		f.child.Move(x, y)
-	}
+	f.layout()
 }
 func (f *Frame) Resize(w, h int) {
 	f.width = w
 	f.height = h
-	if f.child != nil {
-		f.child.Resize(w, h)
+	f.layout()
+}
+
+func (f *Frame) layout() {
+	if f.child == nil {
+		return
 	}
+	r := Rect{X: f.x, Y: f.y, Width: f.width, Height: f.height}
+	place(f.child, f.bounds, r)
+	f.bounds = r
 }
 
-func (f *Frame) Place(win Window) {
+func (f *Frame) OnShow() {
+	if f.hidden {
+		f.hidden = false
+		if f.child != nil {
+			shown(f.child)
+		}
+	}
+}
+func (f *Frame) OnHide() {
+	if !f.hidden {
+		f.hidden = true
+		if f.child != nil {
+			hidden(f.child)
+		}
+	}
+}
+
+//Place places the window in the frame replacing
+//any window that was previously placed.
+func (f *Frame) Place(win Window) error {
+	if win == nil {
+		return ErrNilWindow
+	}
+	if f.child == win {
+		return nil
+	}
+	if f.child != nil {
+		f.Remove(f.child)
+	}
+	adopt(f, win)
 	f.child = win
+	f.bounds = Rect{}
+	mounted(f, win)
+	f.layout()
+	if !f.hidden {
+		shown(win)
+	} else {
+		placedHidden(win)
+	}
+	return nil
+}
+
+//Remove removes the window from the frame.
+func (f *Frame) Remove(win Window) {
+	if win == nil || f.child != win {
+		return
+	}
+	if !f.hidden {
+		hidden(win)
+	}
+	f.child = nil
+	release(win)
+	unmounted(f, win)
+}
+
+//Children returns the window in the frame.
+func (f *Frame) Children() []Window {
+	return []Window{f.child}
+}
+
+func (f *Frame) IndexOf(win Window) int {
+	if win != nil && f.child == win {
+		return 0
+	}
+	return -1
+}
+
+//InsertAt places the window in the frame.
+//The only valid index is 0 and the frame must be empty.
+func (f *Frame) InsertAt(i int, win Window) error {
+	if i != 0 {
+		return ErrIndexOutOfRange
+	}
+	if f.child != nil && f.child != win {
+		return ErrContainerFull
+	}
+	return f.Place(win)
+}
+
+func (f *Frame) Replace(old, win Window) error {
+	if win == nil {
+		return ErrNilWindow
+	}
+	if f.IndexOf(old) < 0 {
+		return ErrNotFound
+	}
+	return f.Place(win)
+}
+
+//Swap is a no-op for the single slot of a frame.
+func (f *Frame) Swap(i, j int) error {
+	if i != 0 || j != 0 {
+		return ErrIndexOutOfRange
+	}
+	return nil
 }
 
 func (f *Frame) Draw() {
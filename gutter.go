@@ -0,0 +1,146 @@
+package termboxui
+
+import (
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/nsf/termbox-go"
+)
+
+//LineNumbers sets how the gutter of a Label numbers the lines.
+type LineNumbers int
+
+const (
+	NoLineNumbers LineNumbers = iota
+	//AbsoluteNumbers numbers the lines from the first line written.
+	//Lines dropped by SetMaxLines keep their numbers counted.
+	AbsoluteNumbers
+	//RelativeNumbers numbers the lines by their distance from the line
+	//at the top of the view, which shows its absolute number.
+	RelativeNumbers
+)
+
+//Sign is a marker drawn in the sign column of the gutter next to a line.
+//Text should be at most 2 cells wide.
+type Sign struct {
+	Text  string
+	Style Style
+}
+
+//Signs for the common uses of the sign column.
+var (
+	SignError    = Sign{Text: "E", Style: Style{Fg: termbox.ColorRed | termbox.AttrBold}}
+	SignWarning  = Sign{Text: "W", Style: Style{Fg: termbox.ColorYellow | termbox.AttrBold}}
+	SignBookmark = Sign{Text: "*", Style: Style{Fg: termbox.ColorCyan}}
+)
+
+//signWidth is the width of the sign column
+const signWidth = 2
+
+//SetLineNumbers shows the line numbers in the gutter of the label.
+func (lbl *Label) SetLineNumbers(mode LineNumbers) {
+	lbl.numbers = mode
+	lbl.checkViewSize()
+}
+
+//SetSignColumn shows or hides the sign column in the gutter of the label.
+func (lbl *Label) SetSignColumn(show bool) {
+	lbl.signs = show
+	lbl.checkViewSize()
+}
+
+//SetGutterStyle sets the style of the line numbers and the gutter.
+func (lbl *Label) SetGutterStyle(style Style) {
+	lbl.gutterStyle = style
+}
+
+//SetSign attaches the sign to the i'th line written to the label.
+//The sign is dropped along with the line.
+func (lbl *Label) SetSign(i int, sign Sign) {
+	if i >= 0 && i < lbl.content.len() {
+		s := sign
+		lbl.content.at(i).sign = &s
+	}
+}
+
+//ClearSign removes the sign of the i'th line.
+func (lbl *Label) ClearSign(i int) {
+	if i >= 0 && i < lbl.content.len() {
+		lbl.content.at(i).sign = nil
+	}
+}
+
+//ClearSigns removes the signs of every line.
+func (lbl *Label) ClearSigns() {
+	for i := 0; i < lbl.content.len(); i++ {
+		lbl.content.at(i).sign = nil
+	}
+}
+
+//gutterWidth returns the number of cells taken by the gutter.
+func (lbl *Label) gutterWidth() int {
+	width := 0
+	if lbl.signs {
+		width += signWidth
+	}
+	if lbl.numbers != NoLineNumbers {
+		//leave room for 2 digits at least so that the
+		//gutter does not grow as soon as the 10th line is written
+		digits := len(strconv.Itoa(lbl.numbered + lbl.content.len()))
+		if digits < 2 {
+			digits = 2
+		}
+		width += digits + 1
+	}
+	return width
+}
+
+//lineNumber returns the number shown next to the i'th line, counting
+//the lines dropped, or the distance to the line at the top of the view
+//with RelativeNumbers.
+func (lbl *Label) lineNumber(i int) int {
+	if lbl.numbers != RelativeNumbers || i == lbl.startLine {
+		return lbl.numbered + i + 1
+	}
+	if i < lbl.startLine {
+		return lbl.startLine - i
+	}
+	return i - lbl.startLine
+}
+
+//drawGutter draws the gutter next to the r'th row of the i'th line
+//on the y'th line of the view.
+func (lbl *Label) drawGutter(y, i, r int, base Style) {
+	style := lbl.gutterStyle.over(base)
+	x, vy := lbl.viewOrigin()
+	start := x
+	put := func(text string, style Style) {
+		for j := 0; j < len(text); {
+			size, width := clusterAt(text[j:])
+			ch, _ := utf8.DecodeRuneInString(text[j:])
+			termbox.SetCell(x, vy+y, ch, style.Fg, style.Bg)
+			x += width
+			j += size
+		}
+	}
+	if lbl.signs {
+		end := x + signWidth
+		if sign := lbl.content.at(i).sign; sign != nil && r == 0 {
+			put(sign.Text, sign.Style.over(style))
+		}
+		for x < end {
+			put(" ", style)
+		}
+	}
+	if lbl.numbers != NoLineNumbers {
+		end := start + lbl.gutter
+		var num string
+		if r == 0 {
+			num = strconv.Itoa(lbl.lineNumber(i))
+		}
+		for x < end-1-len(num) {
+			put(" ", style)
+		}
+		put(num+" ", style)
+	}
+}
@@ -0,0 +1,48 @@
+package termboxui
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestGutterFprintln(t *testing.T) {
+	lbl := NewLabel()
+	lbl.Resize(20, 5)
+	lbl.SetLineNumbers(AbsoluteNumbers)
+	lbl.SetMaxLines(50)
+	for i := 1; i <= 99; i++ {
+		fmt.Fprintln(lbl, "line", i)
+	}
+	lbl.format()
+
+	if got := lbl.LineCount(); got != 50 {
+		t.Fatalf("got %d lines, want 50", got)
+	}
+	//every line is numbered with the line it was written as
+	for i := 0; i < lbl.LineCount(); i++ {
+		want := fmt.Sprint("line ", lbl.lineNumber(i))
+		if got := lbl.content.at(i).text; got != want {
+			t.Fatalf("line %d is numbered %d but is %q", i, lbl.lineNumber(i), got)
+		}
+	}
+	//99 lines have been written, 2 digits and a space
+	if got := lbl.gutterWidth(); got != 3 {
+		t.Errorf("got a gutter %d cells wide, want 3", got)
+	}
+}
+
+func TestGutterRelativeNumbers(t *testing.T) {
+	lbl := NewLabel()
+	lbl.Resize(20, 3)
+	lbl.SetLineNumbers(RelativeNumbers)
+	for i := 1; i <= 10; i++ {
+		fmt.Fprintln(lbl, "line", i)
+	}
+	lbl.ScrollTo(4)
+	want := []int{4, 3, 2, 1, 5, 1, 2}
+	for i, n := range want {
+		if got := lbl.lineNumber(i); got != n {
+			t.Errorf("line %d: got %d, want %d", i, got, n)
+		}
+	}
+}
@@ -0,0 +1,307 @@
+package termboxui
+
+import (
+	"go/scanner"
+	"go/token"
+	"strings"
+
+	"github.com/nsf/termbox-go"
+)
+
+//Highlighter styles lines of source text, such as code.
+//Lines are highlighted in order: state is the state returned for the
+//line before, or 0 for the first line, so that a highlighter can tell
+//whether a line starts inside a multi-line comment or string.
+//The text of the spans returned has to add up to the line.
+type Highlighter interface {
+	Highlight(line string, state int) ([]Span, int)
+}
+
+//HighlightStyles are the styles used by the built in highlighters.
+type HighlightStyles struct {
+	Keyword, Type, String, Number, Comment, Literal Style
+	//Key is the style of the keys of JSON objects
+	Key Style
+	//Header, Hunk, Added and Removed are the styles of the lines of a diff
+	Header, Hunk, Added, Removed Style
+}
+
+//DefaultHighlightStyles is used by the built in highlighters
+//that have no styles set.
+var DefaultHighlightStyles = HighlightStyles{
+	Keyword: Style{Fg: termbox.ColorYellow | termbox.AttrBold},
+	Type:    Style{Fg: termbox.ColorGreen},
+	String:  Style{Fg: termbox.ColorRed},
+	Number:  Style{Fg: termbox.ColorMagenta},
+	Comment: Style{Fg: termbox.ColorBlue},
+	Literal: Style{Fg: termbox.ColorCyan},
+	Key:     Style{Fg: termbox.ColorBlue | termbox.AttrBold},
+	Header:  Style{Fg: termbox.AttrBold},
+	Hunk:    Style{Fg: termbox.ColorCyan},
+	Added:   Style{Fg: termbox.ColorGreen},
+	Removed: Style{Fg: termbox.ColorRed},
+}
+
+func stylesOrDefault(styles *HighlightStyles) *HighlightStyles {
+	if styles == nil {
+		return &DefaultHighlightStyles
+	}
+	return styles
+}
+
+//spanList builds the spans of a line, leaving the text
+//between the styled parts with the default style.
+type spanList struct {
+	line  string
+	spans []Span
+	pos   int
+}
+
+func (l *spanList) add(start, end int, style Style) {
+	if start > l.pos {
+		l.spans = append(l.spans, Span{Text: l.line[l.pos:start]})
+	}
+	if end > start {
+		l.spans = append(l.spans, Span{Text: l.line[start:end], Style: style})
+	}
+	l.pos = end
+}
+
+func (l *spanList) done() []Span {
+	l.add(len(l.line), len(l.line), Style{})
+	return l.spans
+}
+
+//The states of GoHighlighter
+const (
+	goCode = iota
+	goComment
+	goRawString
+)
+
+var goTypes = map[string]bool{
+	"bool": true, "byte": true, "complex64": true, "complex128": true,
+	"error": true, "float32": true, "float64": true, "int": true,
+	"int8": true, "int16": true, "int32": true, "int64": true,
+	"rune": true, "string": true, "uint": true, "uint8": true,
+	"uint16": true, "uint32": true, "uint64": true, "uintptr": true,
+}
+
+var goLiterals = map[string]bool{"true": true, "false": true, "nil": true, "iota": true}
+
+//GoHighlighter highlights Go source code using go/scanner.
+type GoHighlighter struct {
+	Styles *HighlightStyles
+}
+
+func (h GoHighlighter) Highlight(line string, state int) ([]Span, int) {
+	styles := stylesOrDefault(h.Styles)
+	spans := spanList{line: line}
+
+	//finish the comment or raw string left open on the line before
+	start := 0
+	switch state {
+	case goComment:
+		end := strings.Index(line, "*/")
+		if end < 0 {
+			spans.add(0, len(line), styles.Comment)
+			return spans.done(), goComment
+		}
+		start = end + 2
+		spans.add(0, start, styles.Comment)
+	case goRawString:
+		end := strings.IndexByte(line, '`')
+		if end < 0 {
+			spans.add(0, len(line), styles.String)
+			return spans.done(), goRawString
+		}
+		start = end + 1
+		spans.add(0, start, styles.String)
+	}
+
+	src := []byte(line[start:])
+	file := token.NewFileSet().AddFile("", -1, len(src))
+	var s scanner.Scanner
+	s.Init(file, src, func(token.Position, string) {}, scanner.ScanComments)
+	state = goCode
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		if tok == token.SEMICOLON && lit == "\n" {
+			//inserted at the end of the line
+			continue
+		}
+		offset := start + file.Offset(pos)
+		end := offset + len(tok.String())
+		if lit != "" {
+			end = offset + len(lit)
+		}
+
+		var style Style
+		switch {
+		case tok.IsKeyword():
+			style = styles.Keyword
+		case tok == token.IDENT && goTypes[lit]:
+			style = styles.Type
+		case tok == token.IDENT && goLiterals[lit]:
+			style = styles.Literal
+		case tok == token.STRING || tok == token.CHAR:
+			style = styles.String
+			if lit[0] == '`' && (len(lit) == 1 || !strings.HasSuffix(lit, "`")) {
+				state = goRawString
+			}
+		case tok == token.INT || tok == token.FLOAT || tok == token.IMAG:
+			style = styles.Number
+		case tok == token.COMMENT:
+			style = styles.Comment
+			if strings.HasPrefix(lit, "/*") && (len(lit) < 4 || !strings.HasSuffix(lit, "*/")) {
+				state = goComment
+			}
+		default:
+			continue
+		}
+		spans.add(offset, end, style)
+	}
+	return spans.done(), state
+}
+
+//JSONHighlighter highlights JSON, telling the keys of objects
+//apart from string values.
+type JSONHighlighter struct {
+	Styles *HighlightStyles
+}
+
+func (h JSONHighlighter) Highlight(line string, state int) ([]Span, int) {
+	styles := stylesOrDefault(h.Styles)
+	spans := spanList{line: line}
+	for i := 0; i < len(line); {
+		switch ch := line[i]; {
+		case ch == '"':
+			end := i + 1
+			for end < len(line) && line[end] != '"' {
+				if line[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end < len(line) {
+				end++
+			} else {
+				end = len(line)
+			}
+			style := styles.String
+			if strings.HasPrefix(strings.TrimLeft(line[end:], " \t"), ":") {
+				style = styles.Key
+			}
+			spans.add(i, end, style)
+			i = end
+		case ch == '-' || ch >= '0' && ch <= '9':
+			end := i + 1
+			for end < len(line) && strings.IndexByte("0123456789.eE+-", line[end]) >= 0 {
+				end++
+			}
+			spans.add(i, end, styles.Number)
+			i = end
+		case ch >= 'a' && ch <= 'z':
+			end := i + 1
+			for end < len(line) && line[end] >= 'a' && line[end] <= 'z' {
+				end++
+			}
+			switch line[i:end] {
+			case "true", "false", "null":
+				spans.add(i, end, styles.Literal)
+			}
+			i = end
+		default:
+			i++
+		}
+	}
+	return spans.done(), 0
+}
+
+//DiffHighlighter highlights unified diffs.
+type DiffHighlighter struct {
+	Styles *HighlightStyles
+}
+
+func (h DiffHighlighter) Highlight(line string, state int) ([]Span, int) {
+	styles := stylesOrDefault(h.Styles)
+	var style Style
+	switch {
+	case strings.HasPrefix(line, "diff "), strings.HasPrefix(line, "index "),
+		strings.HasPrefix(line, "--- "), strings.HasPrefix(line, "+++ "):
+		style = styles.Header
+	case strings.HasPrefix(line, "@@"):
+		style = styles.Hunk
+	case strings.HasPrefix(line, "+"):
+		style = styles.Added
+	case strings.HasPrefix(line, "-"):
+		style = styles.Removed
+	}
+	return []Span{{Text: line, Style: style}}, 0
+}
+
+//SetHighlighter sets the highlighter styling the lines of the label,
+//replacing the styles they were written with. Lines are highlighted
+//once as they are written. A nil highlighter shows the written styles.
+func (lbl *Label) SetHighlighter(h Highlighter) {
+	lbl.highlighter = h
+	lbl.highlighted = 0
+	lbl.changed = true
+}
+
+//highlight highlights the lines written since it was last called.
+func (lbl *Label) highlight() {
+	if lbl.highlighted > lbl.content.len() {
+		lbl.highlighted = lbl.content.len()
+	}
+	for ; lbl.highlighted < lbl.content.len(); lbl.highlighted++ {
+		lbl.highlightLine(lbl.highlighted)
+	}
+}
+
+//rehighlight highlights again the m lines that replaced the lines from
+//the i'th, along with the lines after them until the state at the end
+//of a line is the same as before.
+func (lbl *Label) rehighlight(i, n, m int) {
+	if lbl.highlighted < i+n {
+		//the lines after i have not been highlighted yet
+		if lbl.highlighted > i {
+			lbl.highlighted = i
+		}
+		return
+	}
+	lbl.highlighted += m - n
+	for j := i; j < lbl.highlighted; j++ {
+		state := lbl.content.at(j).state
+		lbl.highlightLine(j)
+		if j >= i+m && lbl.content.at(j).state == state {
+			break
+		}
+	}
+}
+
+//highlightLine highlights the i'th line starting from
+//the state at the end of the line before.
+func (lbl *Label) highlightLine(i int) {
+	state := 0
+	if i > 0 {
+		state = lbl.content.at(i - 1).state
+	}
+	line := lbl.content.at(i)
+	spans, end := lbl.highlighter.Highlight(line.text, state)
+
+	var styled styledLine
+	for _, span := range spans {
+		styled.add(span.Text, span.Style)
+	}
+	if styled.text == line.text {
+		line.highlit = styled.runs
+	} else {
+		//the spans do not match the text
+		line.highlit = line.runs
+	}
+	line.state = end
+}
@@ -0,0 +1,97 @@
+package termboxui
+
+import "reflect"
+
+//Identifiable is implemented by Windows carrying identity metadata
+//so that they can be looked up in a tree by ID or class.
+type Identifiable interface {
+	ID() string
+	HasClass(class string) bool
+}
+
+//Identity holds an optional ID and a set of classes.
+//It is embedded in the windows provided by this package and
+//can be embedded in custom windows to make them Identifiable.
+type Identity struct {
+	id      string
+	classes []string
+}
+
+func (i *Identity) ID() string        { return i.id }
+func (i *Identity) SetID(id string)   { i.id = id }
+func (i *Identity) Classes() []string { return append([]string(nil), i.classes...) }
+
+//AddClass adds the classes that have not been added yet.
+func (i *Identity) AddClass(classes ...string) {
+	for _, class := range classes {
+		if !i.HasClass(class) {
+			i.classes = append(i.classes, class)
+		}
+	}
+}
+
+//RemoveClass removes the class if it had been added.
+func (i *Identity) RemoveClass(class string) {
+	for j, c := range i.classes {
+		if c == class {
+			i.classes = append(i.classes[:j], i.classes[j+1:]...)
+			return
+		}
+	}
+}
+
+func (i *Identity) HasClass(class string) bool {
+	for _, c := range i.classes {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+//FindFunc returns every window under root, including root,
+//for which match returns true. Windows are listed in the order
+//they are visited by Walk.
+func FindFunc(root Window, match func(Window) bool) []Window {
+	var found []Window
+	Walk(root, func(win Window) error {
+		if match(win) {
+			found = append(found, win)
+		}
+		return nil
+	})
+	return found
+}
+
+//FindByID returns the first window under root with the given ID
+//or nil if there is none.
+func FindByID(root Window, id string) Window {
+	var found Window
+	Walk(root, func(win Window) error {
+		if w, ok := win.(Identifiable); ok && w.ID() == id {
+			found = win
+			return errFound
+		}
+		return nil
+	})
+	return found
+}
+
+//FindByClass returns every window under root with the given class.
+func FindByClass(root Window, class string) []Window {
+	return FindFunc(root, func(win Window) bool {
+		w, ok := win.(Identifiable)
+		return ok && w.HasClass(class)
+	})
+}
+
+//FindAll returns every window under root with the same
+//dynamic type as typ. For example:
+//
+//	labels := FindAll(root, (*Label)(nil))
+func FindAll(root Window, typ Window) []Window {
+	t := reflect.TypeOf(typ)
+	return FindFunc(root, func(win Window) bool {
+		return reflect.TypeOf(win) == t
+	})
+}
@@ -0,0 +1,361 @@
+package termboxui
+
+import (
+	"unicode"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/nsf/termbox-go"
+)
+
+//NewInput creates a new single line text input.
+func NewInput() *Input {
+	return &Input{
+		x: -1, y: -1,
+		placeholderStyle: Style{Fg: termbox.AttrDim},
+	}
+}
+
+//Input is a single line text input. Keys are passed to it with
+//HandleKey and it shows the terminal cursor while it has focus.
+//Text too long for its width is scrolled to keep the cursor in view.
+type Input struct {
+	Identity
+
+	x, y          int
+	width, height int
+
+	value []rune
+	//cursor is the index in value of the rune after the cursor
+	cursor int
+	//scroll is the number of columns of the value left of the view
+	scroll int
+	//killed is the text last removed by a kill, put back by yank
+	killed []rune
+
+	placeholder      string
+	placeholderStyle Style
+	//mask is drawn in place of each rune of the value if set
+	mask rune
+
+	history []string
+	//histPos is the index in history of the value shown, len(history)
+	//for the value being typed, which is kept in draft
+	histPos int
+	draft   []rune
+
+	onSubmit, onChange func(value string)
+
+	focused bool
+	fg, bg  termbox.Attribute
+
+	parent Container
+}
+
+func (in *Input) Origin() (x, y int)        { return in.x, in.y }
+func (in *Input) Size() (width, height int) { return in.width, in.height }
+
+func (in *Input) Parent() Container     { return in.parent }
+func (in *Input) SetParent(c Container) { in.parent = c }
+
+func (in *Input) Move(x, y int) {
+	in.x = x
+	in.y = y
+}
+func (in *Input) Resize(width, height int) {
+	in.width = width
+	in.height = height
+}
+
+func (in *Input) SetFG(attr termbox.Attribute) {
+	in.fg = attr
+}
+func (in *Input) SetBG(attr termbox.Attribute) {
+	in.bg = attr
+}
+
+//OnFocus shows the cursor in the input once it is drawn.
+func (in *Input) OnFocus() {
+	in.focused = true
+}
+
+//OnBlur hides the cursor.
+func (in *Input) OnBlur() {
+	in.focused = false
+	termbox.HideCursor()
+}
+
+//Value returns the text typed in the input.
+func (in *Input) Value() string {
+	return string(in.value)
+}
+
+//SetValue replaces the text of the input and moves the cursor to its end.
+//The change callback is not called.
+func (in *Input) SetValue(value string) {
+	in.value = []rune(value)
+	in.cursor = len(in.value)
+	in.histPos = len(in.history)
+}
+
+//SetPlaceholder sets the text shown while the input is empty.
+func (in *Input) SetPlaceholder(text string, style Style) {
+	in.placeholder, in.placeholderStyle = text, style
+}
+
+//SetMask hides the value by drawing mask in place of each of its
+//characters, such as '*' for passwords. 0 shows the value.
+func (in *Input) SetMask(mask rune) {
+	in.mask = mask
+}
+
+//SetHistory sets the values browsed with the Up and Down keys,
+//oldest first. Submitted values are added to it.
+func (in *Input) SetHistory(history []string) {
+	in.history = append([]string(nil), history...)
+	in.histPos = len(in.history)
+}
+
+//History returns the values browsed with the Up and Down keys.
+func (in *Input) History() []string {
+	return append([]string(nil), in.history...)
+}
+
+//OnSubmit sets the function called with the value when Enter is pressed.
+func (in *Input) OnSubmit(fn func(value string)) {
+	in.onSubmit = fn
+}
+
+//OnChange sets the function called with the value whenever it is edited.
+func (in *Input) OnChange(fn func(value string)) {
+	in.onChange = fn
+}
+
+func (in *Input) changed() {
+	if in.onChange != nil {
+		in.onChange(string(in.value))
+	}
+}
+
+//HandleKey edits the input with a key event and returns whether
+//the key was used. The keys follow readline and emacs:
+//
+//	Left, Right, Ctrl-B, Ctrl-F    move by character
+//	Alt-B, Alt-F                   move by word
+//	Home, End, Ctrl-A, Ctrl-E      move to the start or end
+//	Backspace, Delete, Ctrl-D      delete a character
+//	Ctrl-W, Alt-D                  kill the word before or after the cursor
+//	Ctrl-U, Ctrl-K                 kill to the start or end
+//	Ctrl-Y                         yank the text last killed
+//	Up, Down                       browse the history
+//	Enter                          submit the value
+func (in *Input) HandleKey(ev termbox.Event) bool {
+	if ev.Type != termbox.EventKey {
+		return false
+	}
+	if ev.Mod&termbox.ModAlt != 0 {
+		switch ev.Ch {
+		case 'b':
+			in.cursor = in.wordStart(in.cursor)
+		case 'f':
+			in.cursor = in.wordEnd(in.cursor)
+		case 'd':
+			in.kill(in.cursor, in.wordEnd(in.cursor))
+		default:
+			return false
+		}
+		return true
+	}
+
+	switch ev.Key {
+	case termbox.KeyArrowLeft, termbox.KeyCtrlB:
+		if in.cursor > 0 {
+			in.cursor--
+		}
+	case termbox.KeyArrowRight, termbox.KeyCtrlF:
+		if in.cursor < len(in.value) {
+			in.cursor++
+		}
+	case termbox.KeyHome, termbox.KeyCtrlA:
+		in.cursor = 0
+	case termbox.KeyEnd, termbox.KeyCtrlE:
+		in.cursor = len(in.value)
+	case termbox.KeyBackspace, termbox.KeyBackspace2:
+		if in.cursor > 0 {
+			in.remove(in.cursor-1, in.cursor)
+		}
+	case termbox.KeyDelete, termbox.KeyCtrlD:
+		if in.cursor < len(in.value) {
+			in.remove(in.cursor, in.cursor+1)
+		}
+	case termbox.KeyCtrlW:
+		in.kill(in.wordStart(in.cursor), in.cursor)
+	case termbox.KeyCtrlU:
+		in.kill(0, in.cursor)
+	case termbox.KeyCtrlK:
+		in.kill(in.cursor, len(in.value))
+	case termbox.KeyCtrlY:
+		in.insert(in.killed...)
+	case termbox.KeyArrowUp:
+		in.browse(-1)
+	case termbox.KeyArrowDown:
+		in.browse(1)
+	case termbox.KeyEnter:
+		in.submit()
+	case termbox.KeySpace:
+		in.insert(' ')
+	default:
+		if ev.Ch == 0 {
+			return false
+		}
+		in.insert(ev.Ch)
+	}
+	return true
+}
+
+func (in *Input) insert(runes ...rune) {
+	if len(runes) == 0 {
+		return
+	}
+	value := make([]rune, 0, len(in.value)+len(runes))
+	value = append(value, in.value[:in.cursor]...)
+	value = append(value, runes...)
+	in.value = append(value, in.value[in.cursor:]...)
+	in.cursor += len(runes)
+	in.changed()
+}
+
+//remove removes the runes from start to end, moving the cursor with them.
+func (in *Input) remove(start, end int) {
+	if start >= end {
+		return
+	}
+	in.value = append(in.value[:start], in.value[end:]...)
+	if in.cursor > end {
+		in.cursor -= end - start
+	} else if in.cursor > start {
+		in.cursor = start
+	}
+	in.changed()
+}
+
+//kill removes the runes from start to end so that they can be yanked.
+func (in *Input) kill(start, end int) {
+	if start >= end {
+		return
+	}
+	in.killed = append([]rune(nil), in.value[start:end]...)
+	in.remove(start, end)
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+//wordStart returns the start of the word before i.
+func (in *Input) wordStart(i int) int {
+	for i > 0 && !isWordRune(in.value[i-1]) {
+		i--
+	}
+	for i > 0 && isWordRune(in.value[i-1]) {
+		i--
+	}
+	return i
+}
+
+//wordEnd returns the end of the word after i.
+func (in *Input) wordEnd(i int) int {
+	for i < len(in.value) && !isWordRune(in.value[i]) {
+		i++
+	}
+	for i < len(in.value) && isWordRune(in.value[i]) {
+		i++
+	}
+	return i
+}
+
+//browse shows the value dir steps away in the history,
+//going back to the value being typed past the newest one.
+func (in *Input) browse(dir int) {
+	pos := in.histPos + dir
+	if pos < 0 || pos > len(in.history) {
+		return
+	}
+	if in.histPos == len(in.history) {
+		in.draft = append([]rune(nil), in.value...)
+	}
+	in.histPos = pos
+	if pos == len(in.history) {
+		in.value = append([]rune(nil), in.draft...)
+	} else {
+		in.value = []rune(in.history[pos])
+	}
+	in.cursor = len(in.value)
+	in.changed()
+}
+
+//submit adds the value to the history, passes it to the submit
+//callback and clears the input.
+func (in *Input) submit() {
+	value := string(in.value)
+	if value != "" && (len(in.history) == 0 || in.history[len(in.history)-1] != value) {
+		in.history = append(in.history, value)
+	}
+	in.histPos = len(in.history)
+	in.draft = nil
+	in.value, in.cursor = nil, 0
+	if in.onSubmit != nil {
+		in.onSubmit(value)
+	}
+}
+
+//runeWidth returns the number of cells r is drawn over.
+func (in *Input) runeWidth(r rune) int {
+	if in.mask != 0 {
+		r = in.mask
+	}
+	if w := runewidth.RuneWidth(r); w > 0 {
+		return w
+	}
+	return 1
+}
+
+//Draw draws the value, or the placeholder while it is empty,
+//and places the cursor if the input has focus.
+func (in *Input) Draw() {
+	if in.width <= 0 || in.height <= 0 {
+		return
+	}
+	base := Style{Fg: in.fg, Bg: in.bg}
+
+	//scroll to keep the cursor in view, leaving a cell for
+	//the cursor at the end of the value
+	col := 0
+	for _, r := range in.value[:in.cursor] {
+		col += in.runeWidth(r)
+	}
+	if col < in.scroll {
+		in.scroll = col
+	} else if col >= in.scroll+in.width {
+		in.scroll = col - in.width + 1
+	}
+
+	w := cellWriter{x: in.x, y: in.y, width: in.width, col: -in.scroll}
+	if len(in.value) == 0 && in.placeholder != "" {
+		style := in.placeholderStyle.over(base)
+		for _, r := range in.placeholder {
+			w.put(r, runewidth.RuneWidth(r), style)
+		}
+	}
+	for _, r := range in.value {
+		width := in.runeWidth(r)
+		if in.mask != 0 {
+			r = in.mask
+		}
+		w.put(r, width, base)
+	}
+	w.fill(base)
+
+	if in.focused {
+		termbox.SetCursor(in.x+col-in.scroll, in.y)
+	}
+}
@@ -0,0 +1,101 @@
+package termboxui
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nsf/termbox-go"
+)
+
+func key(k termbox.Key) termbox.Event {
+	return termbox.Event{Type: termbox.EventKey, Key: k}
+}
+
+func alt(ch rune) termbox.Event {
+	return termbox.Event{Type: termbox.EventKey, Mod: termbox.ModAlt, Ch: ch}
+}
+
+func typeText(in *Input, text string) {
+	for _, ch := range text {
+		in.HandleKey(termbox.Event{Type: termbox.EventKey, Ch: ch})
+	}
+}
+
+func TestInputEditing(t *testing.T) {
+	in := NewInput()
+	typeText(in, "hello big world")
+	tests := []struct {
+		name   string
+		ev     termbox.Event
+		value  string
+		cursor int
+	}{
+		{"Home", key(termbox.KeyHome), "hello big world", 0},
+		{"Alt-F", alt('f'), "hello big world", 5},
+		{"Right", key(termbox.KeyArrowRight), "hello big world", 6},
+		{"Alt-D", alt('d'), "hello  world", 6},
+		{"Backspace", key(termbox.KeyBackspace2), "hello world", 5},
+		{"Ctrl-Y", key(termbox.KeyCtrlY), "hellobig world", 8},
+		{"Alt-B", alt('b'), "hellobig world", 0},
+		{"End", key(termbox.KeyEnd), "hellobig world", 14},
+		{"Ctrl-W", key(termbox.KeyCtrlW), "hellobig ", 9},
+		{"Left", key(termbox.KeyArrowLeft), "hellobig ", 8},
+		{"Ctrl-U", key(termbox.KeyCtrlU), " ", 0},
+		{"Ctrl-Y", key(termbox.KeyCtrlY), "hellobig ", 8},
+		{"Ctrl-K", key(termbox.KeyCtrlK), "hellobig", 8},
+		{"Delete at the end", key(termbox.KeyDelete), "hellobig", 8},
+		{"Right at the end", key(termbox.KeyArrowRight), "hellobig", 8},
+	}
+	for _, tt := range tests {
+		if !in.HandleKey(tt.ev) {
+			t.Errorf("%s: the key was not used", tt.name)
+		}
+		if got := in.Value(); got != tt.value || in.cursor != tt.cursor {
+			t.Errorf("%s: got %q with the cursor at %d, want %q at %d", tt.name, got, in.cursor, tt.value, tt.cursor)
+		}
+	}
+}
+
+func TestInputHistory(t *testing.T) {
+	in := NewInput()
+	in.SetHistory([]string{"a", "b"})
+	var submitted []string
+	in.OnSubmit(func(value string) { submitted = append(submitted, value) })
+	typeText(in, "dr")
+
+	steps := []struct {
+		name  string
+		key   termbox.Key
+		value string
+	}{
+		{"Up", termbox.KeyArrowUp, "b"},
+		{"Up", termbox.KeyArrowUp, "a"},
+		{"Up past the oldest", termbox.KeyArrowUp, "a"},
+		{"Down", termbox.KeyArrowDown, "b"},
+		{"Down to the draft", termbox.KeyArrowDown, "dr"},
+		{"Down past the draft", termbox.KeyArrowDown, "dr"},
+		{"Up", termbox.KeyArrowUp, "b"},
+		{"Enter", termbox.KeyEnter, ""},
+	}
+	for _, s := range steps {
+		in.HandleKey(key(s.key))
+		if got := in.Value(); got != s.value {
+			t.Errorf("%s: got %q, want %q", s.name, got, s.value)
+		}
+	}
+	//submitting the newest value again does not repeat it
+	if got, want := in.History(), []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got history %q, want %q", got, want)
+	}
+	typeText(in, "c")
+	in.HandleKey(key(termbox.KeyEnter))
+	if got, want := in.History(), []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got history %q, want %q", got, want)
+	}
+	if want := []string{"b", "c"}; !reflect.DeepEqual(submitted, want) {
+		t.Errorf("got submitted %q, want %q", submitted, want)
+	}
+	if in.HandleKey(key(termbox.KeyArrowUp)); in.Value() != "c" {
+		t.Errorf("Up after submitting: got %q, want %q", in.Value(), "c")
+	}
+}
@@ -10,37 +10,150 @@ import (
 
 //NewLabel creates a new label
 func NewLabel() *Label {
-	lbl := &Label{x: -1, y: -1}
+	lbl := &Label{x: -1, y: -1, padding: 1, tabWidth: DefaultTabWidth}
 	return lbl
 }
 
 //Label creates an area that displays text
 type Label struct {
+	Identity
+
 	x, y          int
 	width, height int
+	//inset is the number of cells between the edges of the label
+	//and the text, taken by the border and the padding
+	inset int
 
+	//changed is set when the content or layout has changed
+	//since the label was last drawn or scrolled
 	changed    bool
 	borders    bool
 	viewHeight int
 	viewWidth  int
 
+	//Title is drawn in the top edge of the border
 	Title   string
-	content []string
+	content lineBuffer
+	//dropped is the number of lines dropped from the start of
+	//content since the buffer was last formatted
+	dropped int
+
+	//follow keeps the view scrolled to the end while following is set
+	follow, following bool
 
-	//The position (index) we are in the content
+	//markup enables parsing of style tags written to the label
+	markup bool
+	//writeStyle is the style set by the markup and escape
+	//sequences written so far
+	writeStyle Style
+	//escape holds an escape sequence split across writes
+	escape string
+	//open is set while the last line has not been ended by a newline
+	//so that the next write continues it
+	open bool
+
+	//The position (index) we are in the content.
+	//startRow is the row of the startLine'th line at the top of the view.
 	startLine, startPos int
 	endLine, endPos     int
+	startRow            int
+	//startOffset is the byte offset in startLine of the text at the top
+	//of the view. reanchor is set once the lines are to be wrapped again
+	//so that startRow is found again from startOffset.
+	startOffset int
+	reanchor    bool
+
+	//layout is increased whenever the lines need to be wrapped again,
+	//see rows. tabWidth is the distance between tab stops.
+	layout   int
+	tabWidth int
+	//counted caches the number of rows for ScrollState
+	counted rowCount
+
+	wrap       WrapMode
+	keepIndent bool
+	hanging    int
+	//marker is drawn at the start of continuation rows
+	marker string
+	//breakClass tailors line breaking, DefaultBreakClass if nil
+	breakClass func(rune) BreakClass
+	//hscroll is the number of columns scrolled to the right
+	hscroll int
+
+	align  Align
+	valign VAlign
+
+	//search is the search whose matches are highlighted, if any
+	search *search
+	//filter hides the lines that do not match it, if set
+	filter *filter
 
-	//buffer contains each row of text formatted so that it has
-	//each string fits in the label
-	buffer [][]byte
+	sel       *selection
+	clipboard Clipboard
+
+	numbers     LineNumbers
+	signs       bool
+	gutterStyle Style
+	//gutter is the width of the gutter left of the text
+	gutter int
+	//numbered is the number of lines dropped since the label
+	//was cleared, so that the lines left keep their numbers
+	numbered int
+
+	highlighter Highlighter
+	//highlighted is the number of lines highlighted so far
+	highlighted int
+	//drawn records the rows last drawn in the view, see PosAt
+	drawn []drawnRow
 
 	fg, bg termbox.Attribute
+	//padding is the number of blank cells between the border and the text
+	padding                 int
+	borderStyle, titleStyle Style
+
+	parent Container
 }
 
+//row is a line of the label once the content has been wrapped.
+//It refers to a range of text in a line of content.
+type row struct {
+	textRange
+	//cont is set on the rows following the first row of a line
+	cont bool
+	//indent is the number of blank cells drawn before the text
+	indent int
+}
+
+//Align sets how the rows of a line are placed within the width of a Label.
+type Align int
+
+const (
+	//AlignDefault uses the alignment of the Label, see SetLineAlign.
+	AlignDefault Align = iota
+	AlignLeft
+	AlignCenter
+	AlignRight
+	//AlignJustify widens the spaces between words so that each row of a
+	//wrapped line fills the width of the label. The last row is left aligned.
+	AlignJustify
+)
+
+//VAlign sets where the text is placed when it takes up fewer rows
+//than the height of a Label.
+type VAlign int
+
+const (
+	AlignTop VAlign = iota
+	AlignMiddle
+	AlignBottom
+)
+
 func (lbl Label) Origin() (x, y int)        { return lbl.x, lbl.y }
 func (lbl Label) Size() (width, height int) { return lbl.width, lbl.height }
 
+func (lbl *Label) Parent() Container     { return lbl.parent }
+func (lbl *Label) SetParent(c Container) { lbl.parent = c }
+
 func (lbl *Label) Move(x, y int) {
 	lbl.x = x
 	lbl.y = y
@@ -53,24 +166,71 @@ func (lbl *Label) Resize(width, height int) {
 	lbl.changed = true
 }
 
+//SetBorders draws a border around the label with the Title in its top
+//edge. The text is laid out inside the border, see SetPadding.
 func (lbl *Label) SetBorders(borders bool) {
 	lbl.borders = borders
 	lbl.checkViewSize()
 }
 
+//SetPadding sets the number of blank cells left between the border and
+//the text on every side. It is 1 by default and only used with borders.
+func (lbl *Label) SetPadding(padding int) {
+	if padding < 0 {
+		padding = 0
+	}
+	lbl.padding = padding
+	lbl.checkViewSize()
+}
+
+//SetBorderStyle sets the styles of the border and of the title.
+func (lbl *Label) SetBorderStyle(border, title Style) {
+	lbl.borderStyle, lbl.titleStyle = border, title
+}
+
+//viewOrigin returns the screen coordinates of the top left cell of the text.
+func (lbl *Label) viewOrigin() (x, y int) {
+	return lbl.x + lbl.inset, lbl.y + lbl.inset
+}
+
 func (lbl *Label) checkViewSize() {
-	lbl.viewHeight = lbl.height
-	lbl.viewWidth = lbl.width
+	viewWidth := lbl.viewWidth
+	lbl.inset = 0
 	if lbl.borders {
-		lbl.viewHeight -= 4
-		lbl.viewWidth -= 4
+		lbl.inset = 1 + lbl.padding
+	}
+	lbl.viewHeight = lbl.height - 2*lbl.inset
+	lbl.viewWidth = lbl.width - 2*lbl.inset
+	lbl.gutter = lbl.gutterWidth()
+	lbl.viewWidth -= lbl.gutter
+	if lbl.viewWidth != viewWidth {
+		lbl.relayout()
 	}
 }
 
+//Clear removes all of the text and scrolls back to the top.
 func (lbl *Label) Clear() {
-	lbl.content = nil
+	lbl.content.clear()
+	lbl.dropped = 0
+	lbl.writeStyle = Style{}
+	lbl.escape = ""
+	lbl.open = false
+	lbl.startLine, lbl.startRow = 0, 0
+	lbl.startOffset, lbl.reanchor = 0, false
 	lbl.startPos = 0
 	lbl.endPos = 0
+	lbl.hscroll = 0
+	lbl.following = lbl.follow
+	lbl.changed = true
+	if lbl.search != nil {
+		lbl.search.reset()
+	}
+	if lbl.filter != nil {
+		lbl.filter.matched = nil
+	}
+	lbl.sel = nil
+	lbl.numbered = 0
+	lbl.highlighted = 0
 }
 
 func (lbl *Label) SetFG(attr termbox.Attribute) {
@@ -80,58 +240,624 @@ func (lbl *Label) SetBG(attr termbox.Attribute) {
 	lbl.bg = attr
 }
 
+//SetMarkup enables or disables parsing of style tags in text written
+//to the label. See ParseMarkup for the syntax of the tags.
+func (lbl *Label) SetMarkup(markup bool) {
+	lbl.markup = markup
+}
+
+//SetFollow enables or disables following the end of the text.
+//While following, the label scrolls down as text is written to it.
+//Scrolling up stops following until the end is scrolled to again.
+func (lbl *Label) SetFollow(follow bool) {
+	lbl.follow = follow
+	lbl.following = follow
+	if follow {
+		lbl.format()
+		lbl.scrollToEnd()
+	}
+}
+
+//Following returns whether the label is scrolling down as text is written.
+func (lbl *Label) Following() bool {
+	return lbl.follow && lbl.following
+}
+
+//SetMaxLines limits the number of lines kept by the label.
+//Once the limit is reached the oldest lines are dropped as new ones
+//are written. A limit of 0 keeps every line.
+func (lbl *Label) SetMaxLines(max int) {
+	lbl.dropped += lbl.content.setMax(max)
+	lbl.changed = true
+}
+
+//format applies the changes made to the content since the label was
+//last drawn or scrolled to the scroll position.
+func (lbl *Label) format() {
+	if !lbl.changed {
+		return
+	}
+	lbl.changed = false
+	lbl.counted.valid = false
+
+	lbl.drop()
+	if lbl.highlighter != nil {
+		lbl.highlight()
+	}
+	//the gutter grows as lines are written
+	lbl.checkViewSize()
+	if lbl.reanchor {
+		lbl.reanchor = false
+		if lbl.startLine >= 0 && lbl.startLine < lbl.content.len() {
+			lbl.startRow = lbl.rowOf(match{line: lbl.startLine, start: lbl.startOffset})
+		}
+	}
+	lbl.normalize()
+	lbl.clampEnd()
+	if lbl.follow && lbl.following {
+		lbl.scrollToEnd()
+	}
+}
+
+//drop applies the lines dropped and written since the last format to
+//the state indexed by line so that it refers to the same lines as content.
+func (lbl *Label) drop() {
+	if lbl.searching() {
+		lbl.search.update(&lbl.content, lbl.dropped)
+	}
+	if lbl.filter != nil {
+		lbl.filter.update(&lbl.content, lbl.dropped)
+	}
+	if lbl.sel != nil && lbl.dropped > 0 && !lbl.sel.drop(lbl.dropped) {
+		lbl.sel = nil
+	}
+	//keep the same text in view as old lines are dropped
+	if lbl.dropped > 0 {
+		lbl.numbered += lbl.dropped
+		if lbl.highlighted -= lbl.dropped; lbl.highlighted < 0 {
+			lbl.highlighted = 0
+		}
+		lbl.startLine -= lbl.dropped
+		if lbl.startLine < 0 {
+			lbl.startLine, lbl.startRow = 0, 0
+		}
+		lbl.dropped = 0
+	}
+}
+
+//normalize moves the scroll position onto a row that exists,
+//skipping lines without any rows.
+func (lbl *Label) normalize() {
+	n := lbl.content.len()
+	if n == 0 || lbl.startLine < 0 {
+		lbl.startLine, lbl.startRow = 0, 0
+		return
+	}
+	if lbl.startLine >= n {
+		lbl.startLine, lbl.startRow = n-1, len(lbl.rows(n-1))
+	}
+	if lbl.startRow < len(lbl.rows(lbl.startLine)) {
+		return
+	}
+	if line, ok := lbl.nextLine(lbl.startLine, 1); ok {
+		lbl.startLine, lbl.startRow = line, 0
+	} else if line, ok := lbl.nextLine(lbl.startLine+1, -1); ok {
+		lbl.startLine, lbl.startRow = line, len(lbl.rows(line))-1
+	} else {
+		lbl.startRow = 0
+	}
+}
+
+//nextLine returns the first line with rows after line in the direction dir.
+func (lbl *Label) nextLine(line, dir int) (int, bool) {
+	for line += dir; line >= 0 && line < lbl.content.len(); line += dir {
+		if len(lbl.rows(line)) > 0 {
+			return line, true
+		}
+	}
+	return line, false
+}
+
+//moveRows moves the position (line, r) by n rows, down if n is positive
+//and up if n is negative. It stops at the first and last rows
+//and returns the new position along with the number of rows moved.
+func (lbl *Label) moveRows(line, r, n int) (int, int, int) {
+	moved := 0
+	for ; n > 0; n-- {
+		if r+1 < len(lbl.rows(line)) {
+			r++
+		} else if next, ok := lbl.nextLine(line, 1); ok {
+			line, r = next, 0
+		} else {
+			break
+		}
+		moved++
+	}
+	for ; n < 0; n++ {
+		if r > 0 {
+			r--
+		} else if prev, ok := lbl.nextLine(line, -1); ok {
+			line, r = prev, len(lbl.rows(prev))-1
+		} else {
+			break
+		}
+		moved++
+	}
+	return line, r, moved
+}
+
+//scrollToEnd scrolls so that the last row is at the bottom of the view.
+func (lbl *Label) scrollToEnd() {
+	line, ok := lbl.nextLine(lbl.content.len(), -1)
+	if !ok {
+		lbl.startLine, lbl.startRow = 0, 0
+		return
+	}
+	lbl.startLine, lbl.startRow, _ = lbl.moveRows(line, len(lbl.rows(line))-1, 1-lbl.viewHeight)
+}
+
+//clampEnd scrolls back if there are rows past the top of the view
+//but the view does not reach down to the last row.
+//It returns whether the scroll position was moved.
+func (lbl *Label) clampEnd() bool {
+	if !lbl.atEnd() {
+		return false
+	}
+	line, ok := lbl.nextLine(lbl.content.len(), -1)
+	if !ok {
+		return false
+	}
+	endLine, endRow, _ := lbl.moveRows(line, len(lbl.rows(line))-1, 1-lbl.viewHeight)
+	if endLine < lbl.startLine || endLine == lbl.startLine && endRow < lbl.startRow {
+		lbl.startLine, lbl.startRow = endLine, endRow
+		return true
+	}
+	return false
+}
+
+//atEnd returns whether the last row is in view.
+func (lbl *Label) atEnd() bool {
+	if lbl.content.len() == 0 {
+		return true
+	}
+	_, _, moved := lbl.moveRows(lbl.startLine, lbl.startRow, lbl.viewHeight)
+	return moved < lbl.viewHeight
+}
+
+//visibleRows calls fn for every row in view, in order, with the
+//index of its line and of the row within the line.
+//It stops early if fn returns false.
+func (lbl *Label) visibleRows(fn func(y, line, i int, r row) bool) {
+	line, i := lbl.startLine, lbl.startRow
+	for y := 0; y < lbl.viewHeight && line < lbl.content.len(); y++ {
+		rows := lbl.rows(line)
+		if i >= len(rows) {
+			break
+		}
+		if !fn(y, line, i, rows[i]) {
+			return
+		}
+		var moved int
+		if line, i, moved = lbl.moveRows(line, i, 1); moved == 0 {
+			return
+		}
+	}
+}
+
 //Draw writes the buffered text onto the screen
 func (lbl *Label) Draw() {
-	if lbl.changed {
-		lbl.buffer = lbl.formatText(lbl.content)
-		lbl.changed = false
+	lbl.format()
+	if lbl.borders {
+		lbl.drawBorder()
 	}
-	if lbl.buffer == nil {
+	if lbl.content.len() == 0 || lbl.width == 0 {
 		return
 	}
 
-	for y := 0; y < lbl.viewHeight && lbl.startLine+y < len(lbl.buffer); y++ {
-		slice := lbl.buffer[lbl.startLine+y][:]
-		for x := 0; x < lbl.viewWidth; x++ {
-			if len(slice) == 0 {
-				termbox.SetCell(x+lbl.x, y+lbl.y, ' ', lbl.fg, lbl.bg)
+	base := Style{Fg: lbl.fg, Bg: lbl.bg}
+	top := 0
+	if lbl.valign != AlignTop {
+		//count the rows in view to place them within the view
+		rows := 0
+		lbl.visibleRows(func(y, line, i int, r row) bool {
+			rows++
+			return true
+		})
+		if _, _, above := lbl.moveRows(lbl.startLine, lbl.startRow, -1); above == 0 && rows < lbl.viewHeight {
+			if lbl.valign == AlignMiddle {
+				top = (lbl.viewHeight - rows) / 2
 			} else {
-				r, size := utf8.DecodeRune(slice)
-				termbox.SetCell(x+lbl.x, y+lbl.y, r, lbl.fg, lbl.bg)
-				slice = slice[size:]
+				top = lbl.viewHeight - rows
 			}
 		}
 	}
+	lbl.drawn = lbl.drawn[:0]
+	for y := 0; y < lbl.viewHeight; y++ {
+		lbl.drawn = append(lbl.drawn, drawnRow{line: -1})
+	}
+	lbl.visibleRows(func(y, line, i int, r row) bool {
+		if y+top >= lbl.viewHeight {
+			return false
+		}
+		if lbl.gutter > 0 {
+			lbl.drawGutter(y+top, line, i, base)
+		}
+		last := i == len(lbl.rows(line))-1
+		lbl.drawRow(y+top, line, r, last, base)
+		return true
+	})
+}
+
+//drawBorder draws the border along the edges of the label
+//with the title in the top edge.
+func (lbl *Label) drawBorder() {
+	if lbl.width < 2 || lbl.height < 2 {
+		return
+	}
+	base := Style{Fg: lbl.fg, Bg: lbl.bg}
+	border := lbl.borderStyle.over(base)
+	drawBox(lbl.x, lbl.y, lbl.width-1, lbl.height-1, border)
+	if lbl.Title == "" {
+		return
+	}
+
+	//the title is kept clear of the corners and cut short if needed
+	title := lbl.titleStyle.over(base)
+	x, end := lbl.x+2, lbl.x+lbl.width-2
+	text := " " + lbl.Title + " "
+	for i := 0; i < len(text); {
+		size, width := clusterAt(text[i:])
+		if x+width > end {
+			break
+		}
+		ch, _ := utf8.DecodeRuneInString(text[i:])
+		termbox.SetCell(x, lbl.y, ch, title.Fg, title.Bg)
+		x += width
+		i += size
+	}
+}
+
+//drawRow draws the row of the i'th line on the y'th line of the view.
+//last is set if it is the last row of its line.
+func (lbl *Label) drawRow(y, i int, r row, last bool, base Style) {
+	line := lbl.content.at(i)
+	text := line.text[r.start:r.end]
+	if lbl.highlighter != nil {
+		highlit := *line
+		highlit.runs = line.highlit
+		line = &highlit
+	}
+
+	var matches []match
+	first := 0
+	if lbl.searching() {
+		matches, first = lbl.search.lineMatches(i)
+	}
+
+	//prefix is the number of cells before the text
+	marker := Span{Text: lbl.marker}
+	if line.prefix != nil {
+		marker = *line.prefix
+	}
+	prefix := r.indent
+	if r.cont {
+		prefix += textWidth(marker.Text)
+	}
+	width := textWidthAt(text, 0, lbl.tabWidth)
+	if r.hyphen {
+		width++
+	}
+	free := lbl.viewWidth - prefix - width
+	if free < 0 {
+		free = 0
+	}
+
+	//offset is the number of cells the text is moved right by and
+	//gaps is the number of spaces the extra space of a justified row
+	//is spread over.
+	offset, gaps := 0, 0
+	align := line.align
+	if align == AlignDefault {
+		align = lbl.align
+	}
+	switch align {
+	case AlignCenter:
+		offset = free / 2
+	case AlignRight:
+		offset = free
+	case AlignJustify:
+		if !last && lbl.wrap != WrapNone {
+			gaps = strings.Count(text, " ")
+		}
+	}
+
+	vx, vy := lbl.viewOrigin()
+	w := cellWriter{x: vx + lbl.gutter, y: vy + y, width: lbl.viewWidth, col: -lbl.hscroll}
+
+	if r.cont {
+		for i := 0; i < len(marker.Text); {
+			size, width := clusterAt(marker.Text[i:])
+			ch, _ := utf8.DecodeRuneInString(marker.Text[i:])
+			w.put(ch, width, marker.Style.over(base))
+			i += size
+		}
+	}
+	for i := 0; i < r.indent+offset; i++ {
+		w.put(' ', 1, base)
+	}
+
+	drawn := &lbl.drawn[y]
+	drawn.line, drawn.end, drawn.cells = i, r.end, drawn.cells[:0]
+
+	run, start, m := 0, w.col, 0
+	for pos := r.start; pos < r.end && w.col < lbl.viewWidth; {
+		size, width := cellWidth(line.text[pos:r.end], w.col-start, lbl.tabWidth)
+		ch, _ := utf8.DecodeRuneInString(line.text[pos:])
+		var style Style
+		style, run = line.styleAt(pos, run)
+		for m < len(matches) && matches[m].end <= pos {
+			m++
+		}
+		if m < len(matches) && matches[m].start <= pos {
+			if first+m == lbl.search.current {
+				style = lbl.search.currentStyle.over(style)
+			} else {
+				style = lbl.search.style.over(style)
+			}
+		}
+		if lbl.selected(i, pos) {
+			style = Style{Fg: termbox.AttrReverse}.over(style)
+		}
+		if w.col+width > 0 {
+			drawn.cells = append(drawn.cells, drawnCell{col: w.col, width: width, start: pos, end: pos + size})
+		}
+		w.putText(ch, width, style.over(base))
+		pos += size
+
+		if ch == ' ' && gaps > 0 {
+			//spread the free space evenly over the remaining gaps
+			extra := free / gaps
+			free -= extra
+			gaps--
+			for ; extra > 0; extra-- {
+				w.put(' ', 1, style.over(base))
+			}
+		}
+	}
+	if r.hyphen {
+		w.put('-', 1, base)
+	}
+	w.fill(base)
 }
 
 //Redraw clears any previous text in the label and then perform a Draw
-func (lbl Label) Overwrite() {
+func (lbl *Label) Overwrite() {
 	Fill(lbl.x, lbl.y, lbl.width, lbl.height, termbox.Cell{Ch: ' '})
 	lbl.Draw()
 }
 
-//Write content to the label
+//Write content to the label.
+//ANSI escape sequences setting colors and attributes are applied
+//to the text and any other escape sequences are removed.
 func (lbl *Label) Write(p []byte) (n int, err error) {
-	lbl.content = append(lbl.content, strings.Split(string(p), "\n")...)
-	lbl.changed = true
+	var spans []Span
+	spans, lbl.writeStyle, lbl.escape = lbl.decode(lbl.escape+string(p), lbl.writeStyle)
+	if spans == nil {
+		//only escape sequences were written
+		return len(p), nil
+	}
+	lbl.writeSpans(spans)
 	return len(p), nil
 }
 
-func (lbl Label) formatText(lines []string) (fmt [][]byte) {
-	if lines == nil || lbl.width == 0 {
-		return nil
+//writeSpans adds the spans to the text. The last line is left open until
+//a newline is written so that text written in pieces stays on one line.
+func (lbl *Label) writeSpans(spans []Span) {
+	lines := spansToLines(spans)
+	open := true
+	if n := len(lines); n > 1 && lines[n-1].text == "" {
+		//a newline at the end ends the last line
+		lines, open = lines[:n-1], false
+	} else if n == 1 && lines[0].text == "" {
+		return
+	}
+	if lbl.open && lbl.content.len() > 0 {
+		lbl.extend(lines[0], len(lines) > 1 || !open)
+		lines = lines[1:]
+	}
+	lbl.dropped += lbl.content.append(lines...)
+	lbl.open = open
+	lbl.changed = true
+}
+
+//extend adds line to the end of the last line, which was left open by the
+//last write. closed is set if a newline was written after line.
+func (lbl *Label) extend(line styledLine, closed bool) {
+	lbl.drop()
+	i := lbl.content.len() - 1
+	last := lbl.content.at(i)
+	merged := styledLine{text: last.text, runs: append([]styleRun(nil), last.runs...),
+		align: last.align, sign: last.sign, prefix: last.prefix}
+	for k, run := range line.runs {
+		end := len(line.text)
+		if k+1 < len(line.runs) {
+			end = line.runs[k+1].start
+		}
+		merged.add(line.text[run.start:end], run.style)
+	}
+	if closed {
+		merged.text = strings.TrimSuffix(merged.text, "\r")
+	}
+	//the start of the line is unchanged so the selection and
+	//the scroll position stay on the same text
+	lbl.content.splice(i, 1, merged)
+	if lbl.searching() {
+		lbl.search.splice(&lbl.content, i, 1, 1)
+	}
+	if lbl.filter != nil {
+		lbl.filter.splice(&lbl.content, i, 1, 1)
 	}
-	// Initialize the buffer
-	fmt = make([][]byte, 0, len(lines))
+	if lbl.highlighter != nil {
+		lbl.rehighlight(i, 1, 1)
+	}
+}
 
-	for _, curLine := range lines {
-		tmp := WrapText(curLine, lbl.viewWidth)
-		for _, wrappedLine := range tmp {
-			fmt = append(fmt, []byte(wrappedLine))
+//decode turns text into spans, applying the ANSI escape sequences and,
+//if enabled, the markup. It returns the style at the end of the text and
+//an escape sequence cut short at the end of the text.
+func (lbl *Label) decode(text string, cur Style) ([]Span, Style, string) {
+	var spans []Span
+	cur, rest := decodeANSI(text, cur, func(text string, style Style) Style {
+		if lbl.markup {
+			var parsed []Span
+			parsed, style = ParseMarkup(text, style)
+			spans = append(spans, parsed...)
+		} else {
+			spans = append(spans, Span{Text: text, Style: style})
 		}
+		return style
+	})
+	return spans, cur, rest
+}
+
+//WriteSpans writes the styled spans to the label the same way as Write.
+func (lbl *Label) WriteSpans(spans ...Span) {
+	lbl.writeSpans(spans)
+}
+
+//SetAlign sets how the lines of the label are aligned horizontally.
+func (lbl *Label) SetAlign(align Align) {
+	lbl.align = align
+}
+
+//SetLineAlign sets the alignment of the i'th line written to the label
+//overriding the alignment of the label. AlignDefault removes the override.
+func (lbl *Label) SetLineAlign(i int, align Align) {
+	if i >= 0 && i < lbl.content.len() {
+		lbl.content.at(i).align = align
 	}
+}
 
-	return
+//SetVAlign sets where the text is placed when it does not fill the label.
+func (lbl *Label) SetVAlign(valign VAlign) {
+	lbl.valign = valign
+}
+
+//ScrollHorizontal scrolls the text amt columns to the right,
+//or to the left if amt is negative.
+//It is limited by the widest row in view.
+//It returns io.EOF if the edge of the text has been reached.
+func (lbl *Label) ScrollHorizontal(amt int) error {
+	lbl.format()
+	max := lbl.widestRow() - lbl.viewWidth
+
+	lbl.hscroll += amt
+	if lbl.hscroll > max {
+		lbl.hscroll = max
+		if lbl.hscroll < 0 {
+			lbl.hscroll = 0
+		}
+		return io.EOF
+	}
+	if lbl.hscroll < 0 {
+		lbl.hscroll = 0
+		return io.EOF
+	}
+	return nil
+}
+
+//widestRow returns the width of the widest row in view.
+func (lbl *Label) widestRow() int {
+	max := 0
+	lbl.visibleRows(func(y, line, i int, r row) bool {
+		w := textWidthAt(lbl.content.at(line).text[r.start:r.end], 0, lbl.tabWidth)
+		if w > max {
+			max = w
+		}
+		return true
+	})
+	return max
+}
+
+//rowCount is the number of rows the lines are wrapped onto and the
+//number of rows above a line, kept until the content or layout changes.
+type rowCount struct {
+	valid bool
+	total int
+	//before is the number of rows of the lines above line
+	line, before int
+}
+
+//countRows returns the number of rows above the startLine'th line and
+//the number of rows of every line. Every line is wrapped the first time
+//the rows are counted after a change, after that only the rows of the
+//lines scrolled over are counted again.
+func (lbl *Label) countRows() (before, total int) {
+	c := &lbl.counted
+	if !c.valid {
+		*c = rowCount{valid: true}
+		for i := 0; i < lbl.content.len(); i++ {
+			c.total += len(lbl.rows(i))
+		}
+	}
+	for c.line < lbl.startLine && c.line < lbl.content.len() {
+		c.before += len(lbl.rows(c.line))
+		c.line++
+	}
+	for c.line > lbl.startLine && c.line > 0 {
+		c.line--
+		c.before -= len(lbl.rows(c.line))
+	}
+	return c.before, c.total
+}
+
+//ScrollState returns the number of rows above the view, the number of
+//rows in view and the number of rows the text is wrapped onto.
+//The rows are counted once after every change, see countRows.
+func (lbl *Label) ScrollState() (offset, visible, total int) {
+	lbl.format()
+	offset, total = lbl.countRows()
+	if lbl.startLine >= 0 && lbl.startLine < lbl.content.len() && lbl.startRow < len(lbl.rows(lbl.startLine)) {
+		offset += lbl.startRow
+	}
+	visible = lbl.viewHeight
+	if visible > total {
+		visible = total
+	}
+	return offset, visible, total
+}
+
+//ScrollTo scrolls so that the offset'th row is at the top of the view.
+func (lbl *Label) ScrollTo(offset int) {
+	lbl.format()
+	if offset < 0 {
+		offset = 0
+	}
+	//past the last row unless offset is within the text
+	lbl.startLine, lbl.startRow = lbl.content.len(), 0
+	for i := 0; i < lbl.content.len(); i++ {
+		n := len(lbl.rows(i))
+		if offset < n {
+			lbl.startLine, lbl.startRow = i, offset
+			break
+		}
+		offset -= n
+	}
+	lbl.normalize()
+	lbl.clampEnd()
+	lbl.following = lbl.atEnd()
+}
+
+//HScrollState returns the number of columns scrolled to the right,
+//the width of the view and the width of the widest row in view.
+func (lbl *Label) HScrollState() (offset, visible, total int) {
+	lbl.format()
+	return lbl.hscroll, lbl.viewWidth, lbl.widestRow()
+}
+
+//ScrollHorizontalTo scrolls offset columns to the right of the start of the rows.
+func (lbl *Label) ScrollHorizontalTo(offset int) {
+	lbl.ScrollHorizontal(offset - lbl.hscroll)
 }
 
 func (lbl *Label) NextPage() error {
@@ -141,18 +867,18 @@ func (lbl *Label) PrevPage() error {
 	return lbl.Scroll(-lbl.viewHeight)
 }
 
+//Scroll scrolls the text down by amt rows, or up if amt is negative.
+//It stops once the last row is at the bottom of the view.
+//It returns io.EOF if the first or last row has been reached.
 func (lbl *Label) Scroll(amt int) error {
-	if lbl.changed {
-		lbl.buffer = lbl.formatText(lbl.content)
-		lbl.changed = false
-	}
-	lbl.startLine += amt
-	if lbl.startLine > len(lbl.buffer) {
-		lbl.startLine = len(lbl.buffer) - 1
-		return io.EOF
-	}
-	if lbl.startLine < 0 {
-		lbl.startLine = 0
+	lbl.format()
+	defer func() {
+		//scrolling up stops following until the end is back in view
+		lbl.following = lbl.atEnd()
+	}()
+	var moved int
+	lbl.startLine, lbl.startRow, moved = lbl.moveRows(lbl.startLine, lbl.startRow, amt)
+	if lbl.clampEnd() || moved < amt || moved < -amt {
 		return io.EOF
 	}
 	return nil
@@ -0,0 +1,78 @@
+package termboxui
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestLabelFprintln(t *testing.T) {
+	lbl := NewLabel()
+	for i := 0; i < 3; i++ {
+		fmt.Fprintln(lbl, "x", i)
+	}
+	if got, want := lbl.Lines(), []string{"x 0", "x 1", "x 2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	fmt.Fprintln(lbl)
+	fmt.Fprint(lbl, "\n")
+	if got := lbl.LineCount(); got != 5 {
+		t.Errorf("empty lines: got %d lines, want 5", got)
+	}
+}
+
+func TestLabelFprintlnMaxLines(t *testing.T) {
+	lbl := NewLabel()
+	lbl.SetMaxLines(4)
+	for i := 0; i < 10; i++ {
+		fmt.Fprintln(lbl, "x", i)
+	}
+	if got, want := lbl.Lines(), []string{"x 6", "x 7", "x 8", "x 9"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	//the open line counts towards the limit
+	fmt.Fprint(lbl, "y")
+	fmt.Fprint(lbl, "z")
+	if got, want := lbl.Lines(), []string{"x 7", "x 8", "x 9", "yz"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("open line: got %q, want %q", got, want)
+	}
+}
+
+func TestLabelWriteAfterEdit(t *testing.T) {
+	lbl := NewLabel()
+	fmt.Fprint(lbl, "a")
+	lbl.InsertLines(1, "b")
+	fmt.Fprint(lbl, "c")
+	if got, want := lbl.Lines(), []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	lbl.SetText("d")
+	fmt.Fprint(lbl, "e")
+	if got, want := lbl.Lines(), []string{"de"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SetText: got %q, want %q", got, want)
+	}
+}
+
+func TestLabelResizeKeepsTop(t *testing.T) {
+	lbl := NewLabel()
+	lbl.Resize(10, 1)
+	lbl.SetText("first\naaaa bbbb cccc dddd eeee ffff\nlast")
+	lbl.Scroll(3)
+	if lbl.startLine != 1 || lbl.startRow != 2 {
+		t.Fatalf("got %d:%d at the top, want 1:2", lbl.startLine, lbl.startRow)
+	}
+
+	//the row holding the text at the top of the view stays at the top
+	lbl.Resize(15, 1)
+	lbl.format()
+	rows := lbl.rows(1)
+	if lbl.startLine != 1 || lbl.startRow >= len(rows) {
+		t.Fatalf("got %d:%d at the top", lbl.startLine, lbl.startRow)
+	}
+	if r := rows[lbl.startRow]; r.start > 20 || r.end <= 20 {
+		t.Errorf("got the row from %d to %d at the top, want the row of offset 20", r.start, r.end)
+	}
+}
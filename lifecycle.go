@@ -0,0 +1,99 @@
+package termboxui
+
+//Rect describes the area occupied by a window.
+type Rect struct {
+	X, Y          int
+	Width, Height int
+}
+
+//The following interfaces are optional. Containers check whether
+//their children implement them and call the hooks as the children
+//are placed, laid out and shown.
+
+//MountHandler is notified after the window has been placed in parent.
+type MountHandler interface {
+	OnMount(parent Container)
+}
+
+//UnmountHandler is notified after the window has been removed from parent.
+type UnmountHandler interface {
+	OnUnmount(parent Container)
+}
+
+//ResizeHandler is notified whenever a container changes the area of
+//the window. A window that has just been placed receives an empty old Rect.
+type ResizeHandler interface {
+	OnResize(old, new Rect)
+}
+
+//FocusHandler is notified by Focus and Blur.
+type FocusHandler interface {
+	OnFocus()
+	OnBlur()
+}
+
+//VisibilityHandler is notified when the window starts or stops being drawn,
+//for example when it is placed in a hidden pane or its pane is restored.
+type VisibilityHandler interface {
+	OnShow()
+	OnHide()
+}
+
+//Focus notifies win that it has gained focus.
+func Focus(win Window) {
+	if h, ok := win.(FocusHandler); ok {
+		h.OnFocus()
+	}
+}
+
+//Blur notifies win that it has lost focus.
+func Blur(win Window) {
+	if h, ok := win.(FocusHandler); ok {
+		h.OnBlur()
+	}
+}
+
+func mounted(parent Container, win Window) {
+	if h, ok := win.(MountHandler); ok {
+		h.OnMount(parent)
+	}
+}
+
+func unmounted(parent Container, win Window) {
+	if h, ok := win.(UnmountHandler); ok {
+		h.OnUnmount(parent)
+	}
+}
+
+//place moves and resizes win into r and notifies it if r differs from old.
+func place(win Window, old, r Rect) {
+	win.Move(r.X, r.Y)
+	win.Resize(r.Width, r.Height)
+	if r == old {
+		return
+	}
+	if h, ok := win.(ResizeHandler); ok {
+		h.OnResize(old, r)
+	}
+}
+
+func shown(win Window) {
+	if h, ok := win.(VisibilityHandler); ok {
+		h.OnShow()
+	}
+}
+
+func hidden(win Window) {
+	if h, ok := win.(VisibilityHandler); ok {
+		h.OnHide()
+	}
+}
+
+//placedHidden tells a container placed where it is not drawn that it is
+//hidden so that it does not show the windows placed in it later.
+//Other windows have not been shown and are not notified.
+func placedHidden(win Window) {
+	if _, ok := win.(Container); ok {
+		hidden(win)
+	}
+}
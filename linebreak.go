@@ -0,0 +1,167 @@
+package termboxui
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
+)
+
+//BreakClass is the line breaking class of a character as described
+//by the Unicode Line Breaking Algorithm (UAX #14).
+//Only the classes needed to wrap text in a terminal are distinguished.
+type BreakClass int
+
+const (
+	BreakAL BreakClass = iota //alphabetic and other ordinary characters
+	BreakID                   //ideographic, breaks are allowed before and after
+	BreakNU                   //numeric
+	BreakSP                   //space, breaks are allowed after a run of spaces
+	BreakBA                   //break after, such as a tab or a dash
+	BreakBB                   //break before
+	BreakHY                   //hyphen-minus, break after except before a number
+	BreakOP                   //opening punctuation, no break after
+	BreakCL                   //closing punctuation, no break before
+	BreakCP                   //closing parenthesis, no break before
+	BreakQU                   //quotation, no break before or after
+	BreakEX                   //exclamation and interrogation, no break before
+	BreakIS                   //infix numeric separator, no break before
+	BreakSY                   //symbols allowing a break after, such as /
+	BreakNS                   //nonstarters such as small kana, no break before
+	BreakPR                   //prefix numeric, such as $
+	BreakPO                   //postfix numeric, such as %
+	BreakGL                   //non-breaking glue, no break before or after
+	BreakWJ                   //word joiner, no break before or after
+	BreakZW                   //zero width space, break after
+	BreakCM                   //combining mark
+)
+
+var breakClasses = map[rune]BreakClass{
+	' ': BreakSP, '\t': BreakBA,
+	'\u200b': BreakZW, '\u2060': BreakWJ, '\ufeff': BreakWJ,
+	'\u00a0': BreakGL, '\u202f': BreakGL, '\u2007': BreakGL, '\u2011': BreakGL,
+	'-': BreakHY, '\u00ad': BreakBA, '|': BreakBA,
+	'\u2010': BreakBA, '\u2012': BreakBA, '–': BreakBA, '—': BreakBA,
+	'´': BreakBB,
+	'(': BreakOP, '[': BreakOP, '{': BreakOP,
+	')': BreakCP, ']': BreakCP, '}': BreakCL,
+	'"': BreakQU, '\'': BreakQU, '«': BreakQU, '»': BreakQU,
+	'‘': BreakQU, '’': BreakQU, '“': BreakQU, '”': BreakQU,
+	'!': BreakEX, '?': BreakEX, '！': BreakEX, '？': BreakEX,
+	',': BreakIS, '.': BreakIS, ':': BreakIS, ';': BreakIS,
+	'/': BreakSY,
+	'$': BreakPR, '+': BreakPR, '\\': BreakPR, '#': BreakPR,
+	'%': BreakPO, '°': BreakPO,
+}
+
+//cjkPunctuation lists the opening, closing and nonstarter CJK punctuation.
+var cjkPunctuation = map[BreakClass]string{
+	BreakOP: "「『（〔［｛〈《【〖〘〚",
+	BreakCL: "、。，．」』）〕］｝〉》】〗〙〛",
+	BreakNS: "々〻ぁぃぅぇぉっゃゅょゎゕゖァィゥェォッャュョヮヵヶーゝゞヽヾ・",
+}
+
+func init() {
+	for class, chars := range cjkPunctuation {
+		for _, r := range chars {
+			breakClasses[r] = class
+		}
+	}
+}
+
+//DefaultBreakClass returns the break class of r following UAX #14.
+func DefaultBreakClass(r rune) BreakClass {
+	if class, ok := breakClasses[r]; ok {
+		return class
+	}
+	switch {
+	case unicode.In(r, unicode.Mn, unicode.Me):
+		return BreakCM
+	case unicode.IsDigit(r):
+		return BreakNU
+	case unicode.IsSpace(r):
+		return BreakSP
+	case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul),
+		runewidth.RuneWidth(r) == 2:
+		return BreakID
+	}
+	return BreakAL
+}
+
+//canBreak returns whether a line can be broken between a character of
+//class a and a character of class b. spaces is set when a is followed
+//by spaces before b.
+func canBreak(a BreakClass, spaces bool, b BreakClass) bool {
+	switch {
+	case b == BreakSP, b == BreakZW:
+		return false
+	case a == BreakZW:
+		return true
+	case b == BreakCL, b == BreakCP, b == BreakEX, b == BreakIS, b == BreakSY:
+		return false
+	case a == BreakOP:
+		return false
+	case spaces:
+		return true
+	case a == BreakWJ, b == BreakWJ, a == BreakGL:
+		return false
+	case b == BreakGL:
+		return a == BreakBA || a == BreakHY
+	case a == BreakQU, b == BreakQU:
+		return false
+	case b == BreakBA, b == BreakHY, b == BreakNS, a == BreakBB:
+		return false
+	}
+
+	alnum := func(c BreakClass) bool { return c == BreakAL || c == BreakNU }
+	switch {
+	case alnum(a) && alnum(b):
+		return false
+	case alnum(a) && b == BreakOP:
+		return false
+	case a == BreakCP && alnum(b):
+		return false
+	case a == BreakIS && alnum(b):
+		return false
+	case a == BreakHY && b == BreakNU, a == BreakSY && b == BreakNU:
+		return false
+	case a == BreakPR && (alnum(b) || b == BreakOP || b == BreakID):
+		return false
+	case a == BreakNU && (b == BreakPO || b == BreakPR):
+		return false
+	case a == BreakPO && alnum(b):
+		return false
+	}
+	return true
+}
+
+//nextBreak returns the length in bytes of text up to the first
+//opportunity to break the line, including any trailing spaces.
+//classOf returns the break class of each character.
+func nextBreak(text string, classOf func(rune) BreakClass) int {
+	var prev BreakClass
+	spaces := false
+	for i := 0; i < len(text); {
+		r, _ := utf8.DecodeRuneInString(text[i:])
+		size, _ := clusterAt(text[i:])
+		class := classOf(r)
+		if class == BreakCM {
+			//a combining mark on its own is treated as alphabetic
+			class = BreakAL
+		}
+		if i > 0 && canBreak(prev, spaces, class) {
+			return i
+		}
+		switch {
+		case class != BreakSP:
+			prev, spaces = class, false
+		case i == 0:
+			//leading spaces can be broken after like any other spaces
+			prev = BreakSP
+		default:
+			spaces = true
+		}
+		i += size
+	}
+	return len(text)
+}
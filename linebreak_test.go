@@ -0,0 +1,135 @@
+package termboxui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCanBreak(t *testing.T) {
+	tests := []struct {
+		a      BreakClass
+		spaces bool
+		b      BreakClass
+		want   bool
+	}{
+		{BreakAL, false, BreakAL, false},
+		{BreakAL, true, BreakAL, true},
+		{BreakAL, false, BreakSP, false},
+		{BreakAL, false, BreakZW, false},
+		{BreakZW, false, BreakAL, true},
+		{BreakAL, true, BreakCL, false},
+		{BreakAL, false, BreakCP, false},
+		{BreakAL, true, BreakEX, false},
+		{BreakAL, false, BreakIS, false},
+		{BreakAL, false, BreakSY, false},
+		{BreakOP, false, BreakAL, false},
+		{BreakOP, true, BreakAL, false},
+		{BreakAL, false, BreakWJ, false},
+		{BreakWJ, false, BreakAL, false},
+		{BreakGL, false, BreakAL, false},
+		{BreakAL, false, BreakGL, false},
+		{BreakBA, false, BreakGL, true},
+		{BreakAL, false, BreakQU, false},
+		{BreakQU, false, BreakAL, false},
+		{BreakAL, false, BreakBA, false},
+		{BreakBA, false, BreakAL, true},
+		{BreakAL, false, BreakHY, false},
+		{BreakHY, false, BreakAL, true},
+		{BreakHY, false, BreakNU, false},
+		{BreakAL, false, BreakNS, false},
+		{BreakBB, false, BreakAL, false},
+		{BreakID, false, BreakID, true},
+		{BreakAL, false, BreakID, true},
+		{BreakID, false, BreakAL, true},
+		{BreakSY, false, BreakAL, true},
+		{BreakSY, false, BreakNU, false},
+		{BreakNU, false, BreakNU, false},
+		{BreakAL, false, BreakOP, false},
+		{BreakCP, false, BreakAL, false},
+		{BreakIS, false, BreakNU, false},
+		{BreakPR, false, BreakNU, false},
+		{BreakPR, false, BreakID, false},
+		{BreakNU, false, BreakPO, false},
+		{BreakPO, false, BreakNU, false},
+	}
+	for _, tt := range tests {
+		if got := canBreak(tt.a, tt.spaces, tt.b); got != tt.want {
+			t.Errorf("canBreak(%d, %v, %d) = %v, want %v", tt.a, tt.spaces, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestNextBreak(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"hello", 5},
+		{"hello world", 6},
+		{"x  y", 3},
+		{"a-b", 2},
+		{"-5", 2},
+		{"日本", 3},
+		{"$100 x", 5},
+		{"100% x", 5},
+		{"(a) b", 4},
+		{"a.b", 3},
+		{"1.5 x", 4},
+		{"a\u200bb", 4},
+		{"a\u00a0b", 4},
+		{"a\u2060b", 5},
+		{"e\u0301x", 4},
+		{"/usr/local", 1},
+	}
+	for _, tt := range tests {
+		if got := nextBreak(tt.text, DefaultBreakClass); got != tt.want {
+			t.Errorf("nextBreak(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	tests := []struct {
+		text string
+		lim  int
+		want []string
+	}{
+		{"hello world", 5, []string{"hello", "world"}},
+		{"hello world", 11, []string{"hello world"}},
+		{"a, b", 2, []string{"a,", "b"}},
+		{"well-known fact", 6, []string{"well-", "known", "fact"}},
+		{"x -5 y", 3, []string{"x", "-5", "y"}},
+		{"price: $100 (incl. tax)", 8, []string{"price:", "$100", "(incl.", "tax)"}},
+		{"/usr/local/bin/something", 10, []string{"/usr/", "local/bin/", "something"}},
+		{"abcdefghij", 4, []string{"abc-", "def-", "ghij"}},
+		{"日本語テキスト", 4, []string{"日本", "語テ", "キス", "ト"}},
+		{"「こんにちは」と言った。", 6, []string{"「こん", "にち", "は」と", "言っ", "た。"}},
+	}
+	for _, tt := range tests {
+		if got := WrapText(tt.text, tt.lim); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("WrapText(%q, %d) = %q, want %q", tt.text, tt.lim, got, tt.want)
+		}
+	}
+}
+
+func TestLabelSetBreakClass(t *testing.T) {
+	lbl := NewLabel()
+	lbl.Resize(12, 5)
+	lbl.SetText("com.example.app.Main")
+	lbl.format()
+	if got := len(lbl.rows(0)); got != 2 || !lbl.rows(0)[0].hyphen {
+		t.Fatalf("default: got %d rows, want the name hyphenated onto 2", got)
+	}
+
+	lbl.SetBreakClass(func(r rune) BreakClass {
+		if r == '.' {
+			return BreakBA
+		}
+		return DefaultBreakClass(r)
+	})
+	lbl.format()
+	rows := lbl.rows(0)
+	if len(rows) != 2 || rows[0].hyphen || rows[0].end != len("com.example.") {
+		t.Errorf("tailored: got rows %+v, want a break after com.example.", rows)
+	}
+}
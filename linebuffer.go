@@ -0,0 +1,95 @@
+package termboxui
+
+//lineBuffer stores the lines of a Label.
+//When max is set it is a ring buffer that drops the oldest
+//lines once more than max lines are added.
+type lineBuffer struct {
+	lines []styledLine
+	//head is the index in lines of the oldest line
+	head int
+	//n is the number of lines stored
+	n   int
+	max int
+}
+
+func (b *lineBuffer) len() int { return b.n }
+
+//at returns the i'th line, counting from the oldest line.
+func (b *lineBuffer) at(i int) *styledLine {
+	if b.max > 0 {
+		i = (b.head + i) % len(b.lines)
+	}
+	return &b.lines[i]
+}
+
+//append adds the lines and returns the number of old lines dropped.
+func (b *lineBuffer) append(lines ...styledLine) (dropped int) {
+	if b.max <= 0 {
+		b.lines = append(b.lines, lines...)
+		b.n = len(b.lines)
+		return 0
+	}
+	for _, line := range lines {
+		if b.n < b.max {
+			if len(b.lines) < b.max {
+				b.lines = append(b.lines, line)
+			} else {
+				b.lines[(b.head+b.n)%b.max] = line
+			}
+			b.n++
+			continue
+		}
+		b.lines[b.head] = line
+		b.head = (b.head + 1) % b.max
+		dropped++
+	}
+	return dropped
+}
+
+//setMax sets the maximum number of lines kept, 0 for no limit.
+//It returns the number of old lines dropped.
+func (b *lineBuffer) setMax(max int) (dropped int) {
+	lines := make([]styledLine, b.n)
+	for i := range lines {
+		lines[i] = *b.at(i)
+	}
+	if max > 0 && len(lines) > max {
+		dropped = len(lines) - max
+		lines = lines[dropped:]
+	}
+	b.lines, b.head, b.n, b.max = lines, 0, len(lines), max
+	return dropped
+}
+
+//splice replaces the n lines from the i'th line with the given lines.
+//The lines are kept even if there are more than max of them,
+//call trim to drop the oldest ones.
+func (b *lineBuffer) splice(i, n int, lines ...styledLine) {
+	if n == len(lines) {
+		for j, line := range lines {
+			*b.at(i + j) = line
+		}
+		return
+	}
+	all := make([]styledLine, 0, b.n-n+len(lines))
+	for j := 0; j < i; j++ {
+		all = append(all, *b.at(j))
+	}
+	all = append(all, lines...)
+	for j := i + n; j < b.n; j++ {
+		all = append(all, *b.at(j))
+	}
+	b.lines, b.head, b.n = all, 0, len(all)
+}
+
+//trim drops the oldest lines past max and returns the number dropped.
+func (b *lineBuffer) trim() (dropped int) {
+	if b.max <= 0 || b.n <= b.max {
+		return 0
+	}
+	return b.setMax(b.max)
+}
+
+func (b *lineBuffer) clear() {
+	b.lines, b.head, b.n = nil, 0, 0
+}
@@ -0,0 +1,137 @@
+package termboxui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+//texts returns the text of every line of b, oldest first.
+func texts(b *lineBuffer) []string {
+	lines := make([]string, b.len())
+	for i := range lines {
+		lines[i] = b.at(i).text
+	}
+	return lines
+}
+
+//plainLines returns a line for each of the texts.
+func plainLines(texts ...string) []styledLine {
+	lines := make([]styledLine, len(texts))
+	for i, text := range texts {
+		lines[i].text = text
+	}
+	return lines
+}
+
+func split(s string) []string {
+	if s == "" {
+		return []string{}
+	}
+	return strings.Split(s, " ")
+}
+
+func TestLineBufferAppend(t *testing.T) {
+	tests := []struct {
+		name    string
+		max     int
+		appends []string
+		want    string
+		dropped int
+	}{
+		{"unbounded", 0, []string{"a b", "c d e"}, "a b c d e", 0},
+		{"below max", 5, []string{"a b", "c"}, "a b c", 0},
+		{"at max", 3, []string{"a b", "c"}, "a b c", 0},
+		{"wraps around", 3, []string{"a b c", "d"}, "b c d", 1},
+		{"wraps around twice", 2, []string{"a b", "c d e"}, "d e", 3},
+		{"one append past max", 2, []string{"a b c d e"}, "d e", 3},
+		{"one line", 1, []string{"a", "b", "c"}, "c", 2},
+	}
+	for _, tt := range tests {
+		b := lineBuffer{max: tt.max}
+		dropped := 0
+		for _, a := range tt.appends {
+			dropped += b.append(plainLines(split(a)...)...)
+		}
+		if got := texts(&b); !reflect.DeepEqual(got, split(tt.want)) || dropped != tt.dropped {
+			t.Errorf("%s: got %q dropping %d, want %q dropping %d", tt.name, got, dropped, tt.want, tt.dropped)
+		}
+	}
+}
+
+func TestLineBufferSetMax(t *testing.T) {
+	tests := []struct {
+		name    string
+		max     int
+		appends string
+		setMax  int
+		want    string
+		dropped int
+	}{
+		{"limit unbounded", 0, "a b c d", 2, "c d", 2},
+		{"remove limit", 3, "a b c d", 0, "b c d", 0},
+		{"shrink wrapped", 3, "a b c d e", 2, "d e", 1},
+		{"grow wrapped", 3, "a b c d e", 5, "c d e", 0},
+	}
+	for _, tt := range tests {
+		b := lineBuffer{max: tt.max}
+		b.append(plainLines(split(tt.appends)...)...)
+		dropped := b.setMax(tt.setMax)
+		if got := texts(&b); !reflect.DeepEqual(got, split(tt.want)) || dropped != tt.dropped {
+			t.Errorf("%s: got %q dropping %d, want %q dropping %d", tt.name, got, dropped, tt.want, tt.dropped)
+		}
+		//the buffer keeps working as a ring once the limit changed
+		b.append(plainLines("x", "y", "z")...)
+		want := append(split(tt.want), "x", "y", "z")
+		if tt.setMax > 0 && len(want) > tt.setMax {
+			want = want[len(want)-tt.setMax:]
+		}
+		if got := texts(&b); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: after append got %q, want %q", tt.name, got, want)
+		}
+	}
+}
+
+func TestLineBufferSplice(t *testing.T) {
+	tests := []struct {
+		name    string
+		max     int
+		appends string
+		i, n    int
+		lines   string
+		want    string
+		trimmed string
+		dropped int
+	}{
+		{"replace", 0, "a b c", 1, 1, "X", "a X c", "a X c", 0},
+		{"replace wrapped", 3, "a b c d e", 0, 1, "X", "X d e", "X d e", 0},
+		{"replace last wrapped", 3, "a b c d", 2, 1, "X", "b c X", "b c X", 0},
+		{"insert", 0, "a b", 1, 0, "X Y", "a X Y b", "a X Y b", 0},
+		{"insert past max", 3, "a b c d", 3, 0, "X Y", "b c d X Y", "d X Y", 2},
+		{"delete", 0, "a b c d", 1, 2, "", "a d", "a d", 0},
+		{"delete wrapped", 3, "a b c d e", 1, 1, "", "c e", "c e", 0},
+		{"delete all", 2, "a b c", 0, 2, "", "", "", 0},
+	}
+	for _, tt := range tests {
+		b := lineBuffer{max: tt.max}
+		b.append(plainLines(split(tt.appends)...)...)
+		b.splice(tt.i, tt.n, plainLines(split(tt.lines)...)...)
+		if got := texts(&b); !reflect.DeepEqual(got, split(tt.want)) {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+		if dropped := b.trim(); dropped != tt.dropped {
+			t.Errorf("%s: trim dropped %d, want %d", tt.name, dropped, tt.dropped)
+		}
+		if got := texts(&b); !reflect.DeepEqual(got, split(tt.trimmed)) {
+			t.Errorf("%s: trimmed to %q, want %q", tt.name, got, tt.trimmed)
+		}
+		b.append(plainLines("z")...)
+		want := append(split(tt.trimmed), "z")
+		if tt.max > 0 && len(want) > tt.max {
+			want = want[1:]
+		}
+		if got := texts(&b); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: after append got %q, want %q", tt.name, got, want)
+		}
+	}
+}
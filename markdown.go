@@ -0,0 +1,632 @@
+package termboxui
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/nsf/termbox-go"
+)
+
+//MarkdownStyles are the styles used to render Markdown.
+type MarkdownStyles struct {
+	//Headings holds the styles of the headings by level,
+	//the last style is used for the deeper levels
+	Headings []Style
+	Emphasis Style
+	Strong   Style
+	Code     Style
+	Link     Style
+	//URL is the style of the address shown after the text of a link
+	URL   Style
+	Quote Style
+	//Border is the style of table borders and horizontal rules
+	Border Style
+}
+
+//DefaultMarkdownStyles is used by the Markdown widgets that have no styles set.
+var DefaultMarkdownStyles = MarkdownStyles{
+	Headings: []Style{
+		{Fg: termbox.ColorCyan | termbox.AttrBold | termbox.AttrUnderline},
+		{Fg: termbox.ColorCyan | termbox.AttrBold},
+		{Fg: termbox.AttrBold},
+	},
+	Emphasis: Style{Fg: termbox.AttrCursive},
+	Strong:   Style{Fg: termbox.AttrBold},
+	Code:     Style{Fg: termbox.ColorYellow},
+	Link:     Style{Fg: termbox.ColorBlue | termbox.AttrUnderline},
+	URL:      Style{Fg: termbox.AttrDim},
+	Quote:    Style{Fg: termbox.AttrDim},
+	Border:   Style{Fg: termbox.AttrDim},
+}
+
+//MarkdownHighlighters are the highlighters used for fenced code blocks
+//by the language named after the opening fence.
+var MarkdownHighlighters = map[string]Highlighter{
+	"go":    GoHighlighter{},
+	"json":  JSONHighlighter{},
+	"diff":  DiffHighlighter{},
+	"patch": DiffHighlighter{},
+}
+
+//NewMarkdown creates a widget showing the rendered Markdown text.
+func NewMarkdown(text string) *Markdown {
+	md := &Markdown{label: NewLabel(), source: text}
+	md.render()
+	return md
+}
+
+//Markdown renders Markdown text: headings, emphasis, lists,
+//block quotes, fenced code blocks, tables and links.
+//The text is wrapped, scrolled, searched and selected the same way as
+//in a Label, but it can only be changed with SetText.
+type Markdown struct {
+	Identity
+
+	//label shows the rendered text
+	label  *Label
+	source string
+	styles *MarkdownStyles
+	//rendered is the width of the view the text was last rendered for
+	rendered int
+}
+
+//SetText replaces the Markdown text shown.
+func (md *Markdown) SetText(text string) {
+	md.source = text
+	md.render()
+}
+
+//Text returns the Markdown text shown.
+func (md *Markdown) Text() string {
+	return md.source
+}
+
+//SetStyles sets the styles used to render the text.
+//nil uses DefaultMarkdownStyles.
+func (md *Markdown) SetStyles(styles *MarkdownStyles) {
+	md.styles = styles
+	md.render()
+}
+
+func (md *Markdown) Origin() (x, y int)        { return md.label.Origin() }
+func (md *Markdown) Size() (width, height int) { return md.label.Size() }
+
+func (md *Markdown) Parent() Container     { return md.label.Parent() }
+func (md *Markdown) SetParent(c Container) { md.label.SetParent(c) }
+
+func (md *Markdown) Move(x, y int) { md.label.Move(x, y) }
+
+//Resize resizes the widget, rendering the text again for the new width.
+func (md *Markdown) Resize(width, height int) {
+	md.label.Resize(width, height)
+	if md.label.viewWidth != md.rendered {
+		md.render()
+	}
+}
+
+func (md *Markdown) Draw() { md.label.Draw() }
+
+func (md *Markdown) SetFG(attr termbox.Attribute) { md.label.SetFG(attr) }
+func (md *Markdown) SetBG(attr termbox.Attribute) { md.label.SetBG(attr) }
+
+//SetBorders draws a border around the text, see Label.SetBorders.
+func (md *Markdown) SetBorders(borders bool) {
+	md.label.SetBorders(borders)
+	md.Resize(md.label.Size())
+}
+
+//SetTitle sets the title drawn in the top edge of the border.
+func (md *Markdown) SetTitle(title string) {
+	md.label.Title = title
+}
+
+//SetPadding sets the number of cells between the border and the text.
+func (md *Markdown) SetPadding(padding int) {
+	md.label.SetPadding(padding)
+	md.Resize(md.label.Size())
+}
+
+func (md *Markdown) SetBorderStyle(border, title Style) { md.label.SetBorderStyle(border, title) }
+func (md *Markdown) SetLineNumbers(mode LineNumbers)    { md.label.SetLineNumbers(mode) }
+func (md *Markdown) SetGutterStyle(style Style)         { md.label.SetGutterStyle(style) }
+
+//Lines returns the text of the rendered lines, without styles.
+func (md *Markdown) Lines() []string { return md.label.Lines() }
+func (md *Markdown) LineCount() int  { return md.label.LineCount() }
+
+func (md *Markdown) Scroll(amt int) error                       { return md.label.Scroll(amt) }
+func (md *Markdown) ScrollHorizontal(amt int) error             { return md.label.ScrollHorizontal(amt) }
+func (md *Markdown) NextPage() error                            { return md.label.NextPage() }
+func (md *Markdown) PrevPage() error                            { return md.label.PrevPage() }
+func (md *Markdown) ScrollState() (offset, visible, total int)  { return md.label.ScrollState() }
+func (md *Markdown) ScrollTo(offset int)                        { md.label.ScrollTo(offset) }
+func (md *Markdown) HScrollState() (offset, visible, total int) { return md.label.HScrollState() }
+func (md *Markdown) ScrollHorizontalTo(offset int)              { md.label.ScrollHorizontalTo(offset) }
+
+//Search highlights the matches of pattern in the rendered text,
+//see Label.Search. The search is kept when the text is rendered again.
+func (md *Markdown) Search(pattern string, opts SearchOptions) error {
+	return md.label.Search(pattern, opts)
+}
+func (md *Markdown) ClearSearch()                       { md.label.ClearSearch() }
+func (md *Markdown) SetMatchStyle(match, current Style) { md.label.SetMatchStyle(match, current) }
+func (md *Markdown) MatchCount() int                    { return md.label.MatchCount() }
+func (md *Markdown) CurrentMatch() int                  { return md.label.CurrentMatch() }
+func (md *Markdown) NextMatch() error                   { return md.label.NextMatch() }
+func (md *Markdown) PrevMatch() error                   { return md.label.PrevMatch() }
+
+//SetFilter shows only the rendered lines for which match returns true,
+//see Label.SetFilter.
+func (md *Markdown) SetFilter(match func(text string) bool, context int) {
+	md.label.SetFilter(match, context)
+}
+func (md *Markdown) SetFilterPattern(pattern string, opts SearchOptions, context int) error {
+	return md.label.SetFilterPattern(pattern, opts, context)
+}
+func (md *Markdown) ClearFilter()    { md.label.ClearFilter() }
+func (md *Markdown) Filtering() bool { return md.label.Filtering() }
+
+//Select selects the rendered text from one position to another,
+//see Label.Select. The selection is cleared when the text is rendered again.
+func (md *Markdown) Select(from, to TextPos)                { md.label.Select(from, to) }
+func (md *Markdown) SelectAll()                             { md.label.SelectAll() }
+func (md *Markdown) ClearSelection()                        { md.label.ClearSelection() }
+func (md *Markdown) Selection() (from, to TextPos, ok bool) { return md.label.Selection() }
+func (md *Markdown) SelectedText() string                   { return md.label.SelectedText() }
+func (md *Markdown) SetClipboard(c Clipboard)               { md.label.SetClipboard(c) }
+func (md *Markdown) Copy() error                            { return md.label.Copy() }
+func (md *Markdown) PosAt(x, y int) (TextPos, bool)         { return md.label.PosAt(x, y) }
+func (md *Markdown) MouseSelect(ev termbox.Event) bool      { return md.label.MouseSelect(ev) }
+func (md *Markdown) ExtendSelection(rows, cells int)        { md.label.ExtendSelection(rows, cells) }
+
+//render replaces the text of the label with the rendered text, keeping
+//the same lines in view. The search and the filter are applied to the
+//new text and the selection is cleared the same way as by Label.Clear.
+func (md *Markdown) render() {
+	styles := md.styles
+	if styles == nil {
+		styles = &DefaultMarkdownStyles
+	}
+	lbl := md.label
+	r := mdRenderer{styles: styles, width: lbl.viewWidth}
+	r.render(strings.Split(strings.Replace(md.source, "\r\n", "\n", -1), "\n"))
+
+	lbl.anchor()
+	startLine, startOffset, hscroll := lbl.startLine, lbl.startOffset, lbl.hscroll
+	lbl.Clear()
+	lbl.content.append(r.lines...)
+	lbl.startLine, lbl.startOffset, lbl.hscroll = startLine, startOffset, hscroll
+	md.rendered = lbl.viewWidth
+	lbl.relayout()
+}
+
+//mdRenderer turns the lines of a Markdown document into styled lines.
+type mdRenderer struct {
+	styles *MarkdownStyles
+	width  int
+	lines  []styledLine
+}
+
+//line adds a line made of the spans. prefix is drawn on the rows
+//the line is wrapped onto after the first.
+func (r *mdRenderer) line(prefix *Span, spans ...Span) {
+	var l styledLine
+	for _, span := range spans {
+		l.add(span.Text, span.Style)
+	}
+	l.prefix = prefix
+	r.lines = append(r.lines, l)
+}
+
+//gap separates two blocks with a blank row.
+func (r *mdRenderer) gap() {
+	if n := len(r.lines); n == 0 || r.lines[n-1].text == " " {
+		return
+	}
+	//an empty line has no rows in a Label, a space keeps the row
+	r.line(nil, Span{Text: " "})
+}
+
+func (r *mdRenderer) render(src []string) {
+	var para []string
+	flush := func() {
+		if len(para) > 0 {
+			r.line(nil, r.inline(strings.Join(para, " "), Style{})...)
+			para = nil
+		}
+	}
+	for i := 0; i < len(src); i++ {
+		line := strings.TrimRight(src[i], " \t")
+		trimmed := strings.TrimLeft(line, " \t")
+		switch {
+		case trimmed == "":
+			flush()
+			r.gap()
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			flush()
+			i = r.code(src, i)
+			r.gap()
+		case isHeading(line):
+			flush()
+			r.heading(line)
+		case isRule(trimmed):
+			flush()
+			width := r.width
+			if width < 1 {
+				width = 1
+			}
+			r.line(nil, Span{Text: strings.Repeat("─", width), Style: r.styles.Border})
+			r.gap()
+		case strings.HasPrefix(trimmed, ">"):
+			flush()
+			i = r.quote(src, i)
+			r.gap()
+		case strings.HasPrefix(trimmed, "|"):
+			flush()
+			i = r.table(src, i)
+			r.gap()
+		case listMarker(line) > 0:
+			flush()
+			r.listItem(line)
+		default:
+			para = append(para, trimmed)
+		}
+	}
+	flush()
+	//drop the trailing gap
+	if n := len(r.lines); n > 0 && r.lines[n-1].text == " " {
+		r.lines = r.lines[:n-1]
+	}
+}
+
+//isHeading returns whether line starts with 1 to 6 #s followed by
+//a space or the end of the line.
+func isHeading(line string) bool {
+	level := len(line) - len(strings.TrimLeft(line, "#"))
+	return level >= 1 && level <= 6 && (level == len(line) || line[level] == ' ' || line[level] == '\t')
+}
+
+func isRule(line string) bool {
+	line = strings.Replace(line, " ", "", -1)
+	if len(line) < 3 {
+		return false
+	}
+	for _, ch := range []string{"-", "*", "_"} {
+		if strings.Trim(line, ch) == "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *mdRenderer) heading(line string) {
+	level := len(line) - len(strings.TrimLeft(line, "#"))
+	text := strings.TrimSpace(strings.TrimRight(strings.TrimSpace(line[level:]), "#"))
+	headings := r.styles.Headings
+	var style Style
+	if len(headings) > 0 {
+		if level > len(headings) {
+			level = len(headings)
+		}
+		style = headings[level-1]
+	}
+	r.gap()
+	r.line(nil, r.inline(text, style)...)
+	r.gap()
+}
+
+//code renders the fenced code block starting on the i'th line
+//and returns the index of its closing fence.
+func (r *mdRenderer) code(src []string, i int) int {
+	open := strings.TrimLeft(src[i], " \t")
+	fence := open[:3]
+	lang := strings.ToLower(strings.TrimSpace(open[3:]))
+	if f := strings.Fields(lang); len(f) > 0 {
+		lang = f[0]
+	}
+	h := MarkdownHighlighters[lang]
+	state := 0
+	prefix := &Span{Text: "  "}
+	for i++; i < len(src); i++ {
+		line := strings.TrimRight(src[i], "\r")
+		if strings.HasPrefix(strings.TrimLeft(line, " \t"), fence) {
+			return i
+		}
+		spans := []Span{{Text: line, Style: r.styles.Code}}
+		if h != nil {
+			spans, state = h.Highlight(line, state)
+			for j := range spans {
+				spans[j].Style = spans[j].Style.over(r.styles.Code)
+			}
+		}
+		if line == "" {
+			spans = []Span{{Text: " "}}
+		}
+		r.line(prefix, append([]Span{{Text: "  "}}, spans...)...)
+	}
+	return i
+}
+
+//quote renders the block quote starting on the i'th line and returns
+//the index of its last line.
+func (r *mdRenderer) quote(src []string, i int) int {
+	var para []string
+	bar := Span{Text: "│ ", Style: r.styles.Quote}
+	flush := func() {
+		if len(para) > 0 {
+			r.line(&bar, append([]Span{bar}, r.inline(strings.Join(para, " "), Style{})...)...)
+			para = nil
+		}
+	}
+	for ; i < len(src); i++ {
+		line := strings.TrimSpace(src[i])
+		if !strings.HasPrefix(line, ">") {
+			break
+		}
+		line = strings.TrimSpace(strings.TrimLeft(line, ">"))
+		if line == "" {
+			flush()
+			r.line(nil, bar)
+			continue
+		}
+		para = append(para, line)
+	}
+	flush()
+	return i - 1
+}
+
+//listMarker returns the length of the list marker at the start
+//of the line, including the indentation, or 0 if there is none.
+func listMarker(line string) int {
+	trimmed := strings.TrimLeft(line, " \t")
+	lead := len(line) - len(trimmed)
+	if len(trimmed) >= 2 && strings.IndexByte("-*+", trimmed[0]) >= 0 && trimmed[1] == ' ' {
+		return lead + 2
+	}
+	digits := len(trimmed) - len(strings.TrimLeftFunc(trimmed, unicode.IsDigit))
+	if digits > 0 && len(trimmed) > digits+1 && strings.IndexByte(".)", trimmed[digits]) >= 0 && trimmed[digits+1] == ' ' {
+		return lead + digits + 2
+	}
+	return 0
+}
+
+func (r *mdRenderer) listItem(line string) {
+	n := listMarker(line)
+	trimmed := strings.TrimLeft(line[:n], " \t")
+	depth := (n - len(trimmed)) / 2
+	marker := strings.TrimSpace(trimmed)
+	if len(marker) == 1 {
+		marker = "•"
+	}
+	lead := strings.Repeat("  ", depth)
+	bullet := lead + marker + " "
+	prefix := &Span{Text: strings.Repeat(" ", textWidth(bullet))}
+	r.line(prefix, append([]Span{{Text: bullet}}, r.inline(strings.TrimSpace(line[n:]), Style{})...)...)
+}
+
+//table renders the table starting on the i'th line and returns
+//the index of its last line.
+func (r *mdRenderer) table(src []string, i int) int {
+	var rows [][][]Span
+	var aligns []Align
+	header := false
+	for ; i < len(src); i++ {
+		line := strings.TrimSpace(src[i])
+		if !strings.HasPrefix(line, "|") {
+			break
+		}
+		cells := splitCells(line)
+		if len(rows) == 1 && isDelimiterRow(cells) {
+			header = true
+			for _, cell := range cells {
+				cell = strings.TrimSpace(cell)
+				switch {
+				case strings.HasPrefix(cell, ":") && strings.HasSuffix(cell, ":"):
+					aligns = append(aligns, AlignCenter)
+				case strings.HasSuffix(cell, ":"):
+					aligns = append(aligns, AlignRight)
+				default:
+					aligns = append(aligns, AlignLeft)
+				}
+			}
+			continue
+		}
+		var row [][]Span
+		for _, cell := range cells {
+			style := Style{}
+			if len(rows) == 0 {
+				style = r.styles.Strong
+			}
+			row = append(row, r.inline(strings.TrimSpace(cell), style))
+		}
+		rows = append(rows, row)
+	}
+
+	var widths []int
+	for _, row := range rows {
+		for c, cell := range row {
+			if c >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if w := spansWidth(cell); w > widths[c] {
+				widths[c] = w
+			}
+		}
+	}
+	border := func(left, mid, right string) {
+		var b strings.Builder
+		b.WriteString(left)
+		for c, w := range widths {
+			if c > 0 {
+				b.WriteString(mid)
+			}
+			b.WriteString(strings.Repeat("─", w+2))
+		}
+		b.WriteString(right)
+		r.line(nil, Span{Text: b.String(), Style: r.styles.Border})
+	}
+
+	bar := Span{Text: "│", Style: r.styles.Border}
+	border("┌", "┬", "┐")
+	for n, row := range rows {
+		spans := []Span{bar}
+		for c, w := range widths {
+			var cell []Span
+			if c < len(row) {
+				cell = row[c]
+			}
+			free := w - spansWidth(cell)
+			left := 0
+			if c < len(aligns) {
+				switch aligns[c] {
+				case AlignCenter:
+					left = free / 2
+				case AlignRight:
+					left = free
+				}
+			}
+			spans = append(spans, Span{Text: strings.Repeat(" ", left+1)})
+			spans = append(spans, cell...)
+			spans = append(spans, Span{Text: strings.Repeat(" ", free-left+1)}, bar)
+		}
+		r.line(nil, spans...)
+		if n == 0 && header {
+			border("├", "┼", "┤")
+		}
+	}
+	border("└", "┴", "┘")
+	return i - 1
+}
+
+func splitCells(line string) []string {
+	line = strings.TrimPrefix(line, "|")
+	line = strings.TrimSuffix(line, "|")
+	return strings.Split(line, "|")
+}
+
+func isDelimiterRow(cells []string) bool {
+	for _, cell := range cells {
+		if strings.Trim(strings.TrimSpace(cell), ":-") != "" || !strings.Contains(cell, "-") {
+			return false
+		}
+	}
+	return true
+}
+
+func spansWidth(spans []Span) int {
+	width := 0
+	for _, span := range spans {
+		width += textWidth(span.Text)
+	}
+	return width
+}
+
+//inline renders the emphasis, code spans and links of text.
+func (r *mdRenderer) inline(text string, base Style) []Span {
+	var spans []Span
+	var strong, em bool
+	var b strings.Builder
+	style := func() Style {
+		s := base
+		if strong {
+			s = r.styles.Strong.over(s)
+		}
+		if em {
+			s = r.styles.Emphasis.over(s)
+		}
+		return s
+	}
+	flush := func() {
+		if b.Len() > 0 {
+			spans = append(spans, Span{Text: b.String(), Style: style()})
+			b.Reset()
+		}
+	}
+	for i := 0; i < len(text); i++ {
+		ch := text[i]
+		switch {
+		case ch == '\\' && i+1 < len(text) && strings.IndexByte("\\`*_[]()#+-.!|>", text[i+1]) >= 0:
+			i++
+			b.WriteByte(text[i])
+		case ch == '`':
+			end := strings.IndexByte(text[i+1:], '`')
+			if end < 0 {
+				b.WriteByte(ch)
+				continue
+			}
+			flush()
+			spans = append(spans, Span{Text: text[i+1 : i+1+end], Style: r.styles.Code.over(style())})
+			i += end + 1
+		case (ch == '*' || ch == '_') && i+1 < len(text) && text[i+1] == ch && (strong || opensEmphasis(text, i, 2)):
+			flush()
+			strong = !strong
+			i++
+		case (ch == '*' || (ch == '_' && emphasisBoundary(text, i))) && (em || opensEmphasis(text, i, 1)):
+			flush()
+			em = !em
+		case ch == '[':
+			close := strings.Index(text[i:], "](")
+			end := -1
+			if close >= 0 {
+				end = strings.IndexByte(text[i+close:], ')')
+			}
+			if close < 0 || end < 0 {
+				b.WriteByte(ch)
+				continue
+			}
+			flush()
+			label := text[i+1 : i+close]
+			url := text[i+close+2 : i+close+end]
+			for _, span := range r.inline(label, style()) {
+				span.Style = r.styles.Link.over(span.Style)
+				spans = append(spans, span)
+			}
+			if url != "" && url != label {
+				spans = append(spans, Span{Text: " <" + url + ">", Style: r.styles.URL.over(style())})
+			}
+			i += close + end
+		case ch == '<' && strings.HasPrefix(text[i+1:], "http"):
+			end := strings.IndexByte(text[i:], '>')
+			if end < 0 {
+				b.WriteByte(ch)
+				continue
+			}
+			flush()
+			spans = append(spans, Span{Text: text[i+1 : i+end], Style: r.styles.Link.over(style())})
+			i += end
+		default:
+			b.WriteByte(ch)
+		}
+	}
+	flush()
+	return spans
+}
+
+//opensEmphasis returns whether the n long delimiter at i starts emphasis:
+//it has to be followed by text and closed later on the line by the same
+//delimiter following text.
+func opensEmphasis(text string, i, n int) bool {
+	delim := text[i : i+n]
+	start := i + n
+	if start >= len(text) || text[start] == ' ' || text[start] == '\t' {
+		return false
+	}
+	for j := start + 1; j+n <= len(text); j++ {
+		if text[j:j+n] != delim || text[j-1] == ' ' || text[j-1] == '\t' {
+			continue
+		}
+		if delim[0] == '*' || emphasisBoundary(text, j) {
+			return true
+		}
+	}
+	return false
+}
+
+//emphasisBoundary returns whether the underscore at i starts or ends
+//emphasis rather than being part of a word such as snake_case.
+func emphasisBoundary(text string, i int) bool {
+	word := func(j int) bool {
+		return j >= 0 && j < len(text) && (unicode.IsLetter(rune(text[j])) || unicode.IsDigit(rune(text[j])))
+	}
+	return !word(i-1) || !word(i+1)
+}
@@ -0,0 +1,144 @@
+package termboxui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nsf/termbox-go"
+)
+
+func renderMarkdown(text string, width int) []string {
+	md := NewMarkdown(text)
+	md.Resize(width, 40)
+	return md.Lines()
+}
+
+func TestMarkdownBlocks(t *testing.T) {
+	text := strings.Join([]string{
+		"# Title",
+		"",
+		"Some *em* and **strong** `code` [link](http://x.y).",
+		"",
+		"- one",
+		"- two",
+		"",
+		"> quoted",
+		"",
+		"```go",
+		"func f() {}",
+		"```",
+		"",
+		"| a | b |",
+		"|---|--:|",
+		"| 1 | 22 |",
+		"",
+		"---",
+		"",
+		"1. first",
+	}, "\n")
+	want := []string{
+		"Title",
+		" ",
+		"Some em and strong code link <http://x.y>.",
+		" ",
+		"• one",
+		"• two",
+		" ",
+		"│ quoted",
+		" ",
+		"  func f() {}",
+		" ",
+		"┌───┬────┐",
+		"│ a │  b │",
+		"├───┼────┤",
+		"│ 1 │ 22 │",
+		"└───┴────┘",
+		" ",
+		"──────────",
+		" ",
+		"1. first",
+	}
+	if got := renderMarkdown(text, 10); !reflect.DeepEqual(got, want) {
+		t.Errorf("got\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestMarkdownInline(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"2 * 3 = 6", "2 * 3 = 6"},
+		{"2 * 3 * 4", "2 * 3 * 4"},
+		{"a *b* c", "a b c"},
+		{"a **b** c", "a b c"},
+		{"**x", "**x"},
+		{"snake_case _x_ y", "snake_case x y"},
+		{"_open snake_case", "_open snake_case"},
+		{`\*not em\*`, "*not em*"},
+		{"<http://x.y>", "http://x.y"},
+		{"#hashtag here", "#hashtag here"},
+		{"####### seven", "####### seven"},
+	}
+	for _, tt := range tests {
+		if got := renderMarkdown(tt.text, 40); !reflect.DeepEqual(got, []string{tt.want}) {
+			t.Errorf("%q: got %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestMarkdownStyles(t *testing.T) {
+	styles := DefaultMarkdownStyles
+	styles.Strong = Style{Fg: termbox.ColorRed | termbox.AttrBold}
+	styles.Emphasis = Style{Fg: termbox.AttrCursive}
+	md := NewMarkdown("**_both_** *em*")
+	md.SetStyles(&styles)
+	md.Resize(40, 5)
+
+	line := md.label.content.at(0)
+	if line.text != "both em" {
+		t.Fatalf("got %q", line.text)
+	}
+	want := Style{Fg: termbox.ColorRed | termbox.AttrBold | termbox.AttrCursive}
+	if got, _ := line.styleAt(0, 0); got != want {
+		t.Errorf("strong emphasis: got %v, want %v", got, want)
+	}
+	if got, _ := line.styleAt(5, 0); got != styles.Emphasis {
+		t.Errorf("emphasis: got %v, want %v", got, styles.Emphasis)
+	}
+}
+
+func TestMarkdownSetTextResets(t *testing.T) {
+	md := NewMarkdown("a paragraph\n\nanother paragraph")
+	md.Resize(40, 5)
+	md.Search("paragraph", SearchOptions{})
+	md.SelectAll()
+	md.SetFilter(func(text string) bool { return strings.Contains(text, "another") }, 0)
+	if got := md.MatchCount(); got != 2 {
+		t.Fatalf("got %d matches, want 2", got)
+	}
+
+	md.SetText("hi\n\nanother hi")
+	if got := md.MatchCount(); got != 0 {
+		t.Errorf("got %d matches after SetText, want 0", got)
+	}
+	if _, _, ok := md.Selection(); ok {
+		t.Error("the selection was kept after SetText")
+	}
+	//the filter applies to the new text
+	md.label.format()
+	if got := md.label.rows(0); got != nil {
+		t.Errorf("got rows %v for a line hidden by the filter", got)
+	}
+	if got := len(md.label.rows(2)); got != 1 {
+		t.Errorf("got %d rows for a line matching the filter, want 1", got)
+	}
+
+	//the search is applied again to the new text
+	md.ClearFilter()
+	md.SetText("paragraph, paragraph")
+	if got := md.MatchCount(); got != 2 {
+		t.Errorf("got %d matches, want 2", got)
+	}
+}
@@ -0,0 +1,421 @@
+package termboxui
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/nsf/termbox-go"
+)
+
+const (
+	//pagerChunk is the number of bytes read at a time while indexing
+	pagerChunk = 64 * 1024
+	//pagerUpdate is the shortest time between two progress updates
+	pagerUpdate = 100 * time.Millisecond
+	//pagerSlack is read past the bytes needed to fill the width of
+	//the view for text that takes no cells, such as escape sequences
+	pagerSlack = 1024
+)
+
+//NewPager creates a pager showing the first size bytes of r.
+//The lines of r are indexed in the background; until the indexing
+//is done the pager shows the lines found so far along with a
+//loading indicator. Only the lines in view are read from r.
+func NewPager(r io.ReaderAt, size int64) *Pager {
+	p := &Pager{x: -1, y: -1, r: r, size: size, stop: make(chan struct{}), tabWidth: DefaultTabWidth}
+	if size > 0 {
+		p.lines = []int64{0}
+	}
+	go p.index()
+	return p
+}
+
+//OpenPager opens the named file and creates a pager showing it.
+//The file is closed by Close.
+func OpenPager(name string) (*Pager, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	p := NewPager(f, info.Size())
+	p.closer = f
+	return p, nil
+}
+
+//Pager is a read only view of text too large to be kept in memory,
+//such as a log file. Lines are not wrapped, use ScrollHorizontal to
+//see the text past the right edge. ANSI escape sequences setting
+//colors and attributes are applied the same way as by Label.
+type Pager struct {
+	Identity
+
+	x, y          int
+	width, height int
+
+	r      io.ReaderAt
+	size   int64
+	closer io.Closer
+	stop   chan struct{}
+	//closing guards closing stop and closer, closeErr is the error
+	//returned by every Close
+	closing  sync.Once
+	closeErr error
+
+	//mu guards the fields set while indexing
+	mu sync.Mutex
+	//lines holds the offset of the start of every line found so far
+	lines []int64
+	//indexed is the number of bytes indexed
+	indexed int64
+	done    bool
+	err     error
+	update  func()
+
+	//top is the line at the top of the view
+	top     int
+	hscroll int
+	//tabWidth is the distance between tab stops
+	tabWidth int
+
+	fg, bg termbox.Attribute
+
+	parent Container
+}
+
+func (p *Pager) Origin() (x, y int)        { return p.x, p.y }
+func (p *Pager) Size() (width, height int) { return p.width, p.height }
+
+func (p *Pager) Parent() Container     { return p.parent }
+func (p *Pager) SetParent(c Container) { p.parent = c }
+
+func (p *Pager) Move(x, y int) {
+	p.x = x
+	p.y = y
+}
+func (p *Pager) Resize(width, height int) {
+	p.width = width
+	p.height = height
+}
+
+func (p *Pager) SetFG(attr termbox.Attribute) {
+	p.fg = attr
+}
+func (p *Pager) SetBG(attr termbox.Attribute) {
+	p.bg = attr
+}
+
+//SetTabWidth sets the distance between tab stops, DefaultTabWidth
+//by default.
+func (p *Pager) SetTabWidth(width int) {
+	if width < 1 {
+		width = 1
+	}
+	p.tabWidth = width
+}
+
+//SetUpdateFunc sets a function called from the indexing goroutine as
+//more lines are found and once indexing is done, so that the pager can
+//be drawn again. Indexing waits for fn to return so it should not block.
+//termbox.Interrupt blocks until PollEvent is called, use a buffered
+//channel read by the event loop instead.
+func (p *Pager) SetUpdateFunc(fn func()) {
+	p.mu.Lock()
+	p.update = fn
+	p.mu.Unlock()
+}
+
+//Loading returns whether the lines are still being indexed.
+func (p *Pager) Loading() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return !p.done
+}
+
+//LineCount returns the number of lines indexed so far.
+func (p *Pager) LineCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.lines)
+}
+
+//Err returns the error that stopped the indexing, if any.
+func (p *Pager) Err() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
+}
+
+//Close stops the indexing and closes the file opened by OpenPager.
+//It can be called more than once.
+func (p *Pager) Close() error {
+	p.closing.Do(func() {
+		close(p.stop)
+		if p.closer != nil {
+			p.closeErr = p.closer.Close()
+		}
+	})
+	return p.closeErr
+}
+
+//index finds the start of every line of the text.
+func (p *Pager) index() {
+	buf := make([]byte, pagerChunk)
+	var last time.Time
+	var off int64
+	for off < p.size {
+		select {
+		case <-p.stop:
+			return
+		default:
+		}
+		chunk := buf
+		if rest := p.size - off; rest < int64(len(chunk)) {
+			chunk = chunk[:rest]
+		}
+		n, err := p.r.ReadAt(chunk, off)
+
+		var found []int64
+		for i := 0; i < n; {
+			j := bytes.IndexByte(chunk[i:n], '\n')
+			if j < 0 {
+				break
+			}
+			i += j + 1
+			if start := off + int64(i); start < p.size {
+				found = append(found, start)
+			}
+		}
+		off += int64(n)
+
+		p.mu.Lock()
+		p.lines = append(p.lines, found...)
+		p.indexed = off
+		if err != nil && err != io.EOF {
+			p.err = err
+		}
+		stopped := p.err != nil || n == 0
+		p.mu.Unlock()
+		if stopped {
+			break
+		}
+		if time.Since(last) >= pagerUpdate {
+			last = time.Now()
+			p.notify()
+		}
+	}
+	p.mu.Lock()
+	p.done = true
+	p.mu.Unlock()
+	p.notify()
+}
+
+func (p *Pager) notify() {
+	p.mu.Lock()
+	fn := p.update
+	p.mu.Unlock()
+	if fn != nil {
+		fn()
+	}
+}
+
+//span returns the byte range of the i'th line. The range does not
+//include the line ending, except that of the last line.
+//The caller must hold mu.
+func (p *Pager) span(i int) (start, end int64) {
+	start = p.lines[i]
+	if i+1 < len(p.lines) {
+		end = p.lines[i+1] - 1
+	} else if p.done {
+		end = p.size
+	} else {
+		end = p.indexed
+	}
+	if end < start {
+		end = start
+	}
+	return start, end
+}
+
+//visibleLines reads the lines in view. Each line is limited to the
+//bytes needed to fill the view scrolled by hscroll.
+func (p *Pager) visibleLines() []styledLine {
+	p.mu.Lock()
+	type lineSpan struct{ start, end int64 }
+	var spans []lineSpan
+	for i := p.top; i < len(p.lines) && len(spans) < p.height; i++ {
+		start, end := p.span(i)
+		spans = append(spans, lineSpan{start, end})
+	}
+	p.mu.Unlock()
+
+	limit := int64((p.hscroll+p.width)*utf8.UTFMax + pagerSlack)
+	lines := make([]styledLine, len(spans))
+	for i, s := range spans {
+		if s.end-s.start > limit {
+			s.end = s.start + limit
+		}
+		buf := make([]byte, s.end-s.start)
+		n, _ := p.r.ReadAt(buf, s.start)
+		text := strings.TrimSuffix(string(buf[:n]), "\n")
+		text = strings.TrimSuffix(text, "\r")
+		decodeANSI(text, Style{}, func(text string, style Style) Style {
+			lines[i].add(text, style)
+			return style
+		})
+	}
+	return lines
+}
+
+//Draw draws the lines in view along with a loading indicator
+//while the lines are being indexed.
+func (p *Pager) Draw() {
+	if p.width <= 0 || p.height <= 0 {
+		return
+	}
+	base := Style{Fg: p.fg, Bg: p.bg}
+	lines := p.visibleLines()
+	for y := 0; y < p.height; y++ {
+		var line styledLine
+		if y < len(lines) {
+			line = lines[y]
+		}
+		p.drawLine(y, &line, base)
+	}
+
+	p.mu.Lock()
+	done, indexed, count := p.done, p.indexed, len(p.lines)
+	p.mu.Unlock()
+	if done {
+		return
+	}
+	status := " Loading " + strconv.Itoa(count) + " lines"
+	if p.size > 0 {
+		status += " (" + strconv.FormatInt(indexed*100/p.size, 10) + "%)"
+	}
+	status += " "
+	x := p.width - len(status)
+	if x < 0 {
+		x = 0
+	}
+	for i, ch := range status {
+		if x+i < p.width {
+			termbox.SetCell(p.x+x+i, p.y+p.height-1, ch, base.Fg|termbox.AttrReverse, base.Bg)
+		}
+	}
+}
+
+//drawLine draws the line on the y'th line of the view.
+func (p *Pager) drawLine(y int, line *styledLine, base Style) {
+	w := cellWriter{x: p.x, y: p.y + y, width: p.width, col: -p.hscroll}
+	run := 0
+	for pos := 0; pos < len(line.text) && w.col < p.width; {
+		size, width := cellWidth(line.text[pos:], w.col+p.hscroll, p.tabWidth)
+		ch, _ := utf8.DecodeRuneInString(line.text[pos:])
+		var style Style
+		style, run = line.styleAt(pos, run)
+		w.putText(ch, width, style.over(base))
+		pos += size
+	}
+	w.fill(base)
+}
+
+//ScrollHorizontal scrolls the text amt columns to the right,
+//or to the left if amt is negative.
+//It is limited by the widest line in view.
+//It returns io.EOF if the edge of the text has been reached.
+func (p *Pager) ScrollHorizontal(amt int) error {
+	max := p.widestLine() - p.width
+
+	p.hscroll += amt
+	if p.hscroll > max {
+		p.hscroll = max
+		if p.hscroll < 0 {
+			p.hscroll = 0
+		}
+		return io.EOF
+	}
+	if p.hscroll < 0 {
+		p.hscroll = 0
+		return io.EOF
+	}
+	return nil
+}
+
+//widestLine returns the width of the widest line in view.
+func (p *Pager) widestLine() int {
+	max := 0
+	for _, line := range p.visibleLines() {
+		if w := textWidthAt(line.text, 0, p.tabWidth); w > max {
+			max = w
+		}
+	}
+	return max
+}
+
+//ScrollState returns the index of the line at the top of the view,
+//the number of lines in view and the number of lines indexed so far.
+func (p *Pager) ScrollState() (offset, visible, total int) {
+	total = p.LineCount()
+	visible = total - p.top
+	if visible > p.height {
+		visible = p.height
+	}
+	return p.top, visible, total
+}
+
+//ScrollTo scrolls so that the offset'th line is at the top of the view.
+func (p *Pager) ScrollTo(offset int) {
+	p.Scroll(offset - p.top)
+}
+
+//HScrollState returns the number of columns scrolled to the right,
+//the width of the view and the width of the widest line in view.
+func (p *Pager) HScrollState() (offset, visible, total int) {
+	return p.hscroll, p.width, p.widestLine()
+}
+
+//ScrollHorizontalTo scrolls offset columns to the right of the start of the lines.
+func (p *Pager) ScrollHorizontalTo(offset int) {
+	p.ScrollHorizontal(offset - p.hscroll)
+}
+
+func (p *Pager) NextPage() error {
+	return p.Scroll(p.height)
+}
+func (p *Pager) PrevPage() error {
+	return p.Scroll(-p.height)
+}
+
+//Scroll scrolls the text down by amt lines, or up if amt is negative.
+//It stops once the last line indexed so far is at the bottom of the view.
+//It returns io.EOF if the first or last line has been reached.
+func (p *Pager) Scroll(amt int) error {
+	p.mu.Lock()
+	max := len(p.lines) - p.height
+	p.mu.Unlock()
+	if max < 0 {
+		max = 0
+	}
+
+	p.top += amt
+	if p.top > max {
+		p.top = max
+		return io.EOF
+	}
+	if p.top < 0 {
+		p.top = 0
+		return io.EOF
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package termboxui
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestPagerCloseTwice(t *testing.T) {
+	f, err := ioutil.TempFile("", "pager")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString("a\nb\n")
+	f.Close()
+
+	p, err := OpenPager(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := p.Close(); err != nil {
+			t.Errorf("Close %d: %v", i+1, err)
+		}
+	}
+}
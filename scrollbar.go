@@ -0,0 +1,209 @@
+package termboxui
+
+import "github.com/nsf/termbox-go"
+
+//Scrollable is implemented by the windows whose content can be scrolled
+//vertically, such as Label and Pager.
+type Scrollable interface {
+	//ScrollState returns the number of lines above the view, the number
+	//of lines in view and the number of lines of the content. Windows
+	//wrapping text count the rows the text is wrapped onto.
+	ScrollState() (offset, visible, total int)
+	//ScrollTo scrolls so that the offset'th line, counted the same way
+	//as by ScrollState, is at the top of the view.
+	ScrollTo(offset int)
+}
+
+//HScrollable is implemented by the windows whose content can be
+//scrolled horizontally.
+type HScrollable interface {
+	//HScrollState returns the number of columns left of the view, the
+	//number of columns in view and the width of the content in view.
+	HScrollState() (offset, visible, total int)
+	ScrollHorizontalTo(offset int)
+}
+
+//hscrollable lets a Scrollbar scroll an HScrollable.
+type hscrollable struct {
+	HScrollable
+}
+
+func (h hscrollable) ScrollState() (offset, visible, total int) { return h.HScrollState() }
+func (h hscrollable) ScrollTo(offset int)                       { h.ScrollHorizontalTo(offset) }
+
+//wheelLines is the number of lines scrolled by a turn of the mouse wheel
+const wheelLines = 3
+
+//NewScrollbar creates a vertical scrollbar showing where target is scrolled to.
+func NewScrollbar(target Scrollable) *Scrollbar {
+	return &Scrollbar{
+		x: -1, y: -1,
+		target: target,
+		track:  Style{Fg: termbox.AttrDim},
+	}
+}
+
+//NewHScrollbar creates a horizontal scrollbar showing where target is
+//scrolled to.
+func NewHScrollbar(target HScrollable) *Scrollbar {
+	sb := NewScrollbar(hscrollable{target})
+	sb.horizontal = true
+	return sb
+}
+
+//Scrollbar shows the part of the content of a window that is in view
+//with a thumb sized in proportion to it. The thumb can be dragged and
+//clicking the track scrolls to that point, see MouseScroll.
+type Scrollbar struct {
+	Identity
+
+	x, y          int
+	width, height int
+
+	target     Scrollable
+	horizontal bool
+
+	//dragging is set while the thumb is dragged and grab is the
+	//cell of the thumb the mouse is holding
+	dragging bool
+	grab     int
+
+	track, thumb Style
+
+	parent Container
+}
+
+func (sb *Scrollbar) Origin() (x, y int)        { return sb.x, sb.y }
+func (sb *Scrollbar) Size() (width, height int) { return sb.width, sb.height }
+
+func (sb *Scrollbar) Parent() Container     { return sb.parent }
+func (sb *Scrollbar) SetParent(c Container) { sb.parent = c }
+
+func (sb *Scrollbar) Move(x, y int) {
+	sb.x = x
+	sb.y = y
+}
+func (sb *Scrollbar) Resize(width, height int) {
+	sb.width = width
+	sb.height = height
+}
+
+//SetStyle sets the styles of the track and the thumb.
+func (sb *Scrollbar) SetStyle(track, thumb Style) {
+	sb.track, sb.thumb = track, thumb
+}
+
+//length returns the number of cells along the scrollbar.
+func (sb *Scrollbar) length() int {
+	if sb.horizontal {
+		return sb.width
+	}
+	return sb.height
+}
+
+//thumbSpan returns the first cell and the number of cells of the thumb.
+func (sb *Scrollbar) thumbSpan() (start, size int) {
+	length := sb.length()
+	offset, visible, total := sb.target.ScrollState()
+	if total <= visible || total <= 0 {
+		return 0, length
+	}
+	size = length * visible / total
+	if size < 1 {
+		size = 1
+	}
+	if max := total - visible; offset >= max {
+		start = length - size
+	} else if offset > 0 {
+		start = (length - size) * offset / max
+	}
+	return start, size
+}
+
+func (sb *Scrollbar) Draw() {
+	length := sb.length()
+	if length <= 0 || sb.target == nil {
+		return
+	}
+	start, size := sb.thumbSpan()
+	for i := 0; i < length; i++ {
+		ch, style := '│', sb.track
+		if sb.horizontal {
+			ch = '─'
+		}
+		if i >= start && i < start+size {
+			ch, style = '█', sb.thumb
+		}
+		x, y := sb.x, sb.y+i
+		if sb.horizontal {
+			x, y = sb.x+i, sb.y
+		}
+		termbox.SetCell(x, y, ch, style.Fg, style.Bg)
+	}
+}
+
+//scrollToCell scrolls the target so that the thumb starts at the cell.
+func (sb *Scrollbar) scrollToCell(cell int) {
+	_, visible, total := sb.target.ScrollState()
+	_, size := sb.thumbSpan()
+	if room := sb.length() - size; room > 0 && total > visible {
+		if cell < 0 {
+			cell = 0
+		}
+		if cell > room {
+			cell = room
+		}
+		sb.target.ScrollTo((total - visible) * cell / room)
+	}
+}
+
+//MouseScroll scrolls the target with the mouse: dragging the thumb,
+//clicking the track to move the thumb there and turning the wheel over
+//the scrollbar. It returns whether the event was used.
+func (sb *Scrollbar) MouseScroll(ev termbox.Event) bool {
+	if ev.Type != termbox.EventMouse || sb.target == nil {
+		return false
+	}
+	inside := ev.MouseX >= sb.x && ev.MouseX < sb.x+sb.width &&
+		ev.MouseY >= sb.y && ev.MouseY < sb.y+sb.height
+	cell := ev.MouseY - sb.y
+	if sb.horizontal {
+		cell = ev.MouseX - sb.x
+	}
+
+	switch ev.Key {
+	case termbox.MouseLeft:
+		if sb.dragging && ev.Mod&termbox.ModMotion != 0 {
+			sb.scrollToCell(cell - sb.grab)
+			return true
+		}
+		if !inside {
+			return false
+		}
+		start, size := sb.thumbSpan()
+		if cell < start || cell >= start+size {
+			//center the thumb on the click
+			sb.scrollToCell(cell - size/2)
+			start, _ = sb.thumbSpan()
+		}
+		sb.dragging, sb.grab = true, cell-start
+		return true
+	case termbox.MouseRelease:
+		if sb.dragging {
+			sb.dragging = false
+			return true
+		}
+	case termbox.MouseWheelUp, termbox.MouseWheelDown:
+		if !inside {
+			return false
+		}
+		offset, _, _ := sb.target.ScrollState()
+		if ev.Key == termbox.MouseWheelUp {
+			sb.target.ScrollTo(offset - wheelLines)
+		} else {
+			sb.target.ScrollTo(offset + wheelLines)
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package termboxui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+//countAll counts the rows of every line the same way ScrollState did
+//before the rows were cached.
+func countAll(lbl *Label) (offset, total int) {
+	for i := 0; i < lbl.content.len(); i++ {
+		n := len(lbl.rows(i))
+		switch {
+		case i < lbl.startLine:
+			offset += n
+		case i == lbl.startLine && lbl.startRow < n:
+			offset += lbl.startRow
+		}
+		total += n
+	}
+	return offset, total
+}
+
+func TestLabelScrollState(t *testing.T) {
+	lbl := NewLabel()
+	lbl.Resize(10, 4)
+	for i := 0; i < 20; i++ {
+		fmt.Fprintln(lbl, strings.Repeat("word ", i%4+1))
+	}
+	check := func(step string) {
+		t.Helper()
+		offset, visible, total := lbl.ScrollState()
+		wantOffset, wantTotal := countAll(lbl)
+		if offset != wantOffset || visible != 4 || total != wantTotal {
+			t.Errorf("%s: got %d %d %d, want %d 4 %d", step, offset, visible, total, wantOffset, wantTotal)
+		}
+	}
+	check("written")
+	lbl.Scroll(7)
+	check("scrolled down")
+	lbl.Scroll(-3)
+	check("scrolled up")
+	lbl.ScrollTo(25)
+	check("ScrollTo")
+	fmt.Fprintln(lbl, "more")
+	check("written again")
+	lbl.Resize(6, 4)
+	check("resized")
+	lbl.SetFilterPattern("word word", SearchOptions{}, 0)
+	check("filtered")
+	lbl.DeleteLines(0, 5)
+	check("deleted")
+	lbl.ScrollTo(0)
+	check("top")
+}
@@ -0,0 +1,315 @@
+package termboxui
+
+import (
+	"regexp"
+	"sort"
+	"unicode"
+
+	"github.com/nsf/termbox-go"
+)
+
+//CaseMode sets how a search treats upper and lower case letters.
+type CaseMode int
+
+const (
+	CaseSensitive CaseMode = iota
+	IgnoreCase
+	//SmartCase ignores case unless the pattern has an upper case letter.
+	SmartCase
+)
+
+//SearchOptions sets how the pattern of a search is matched.
+type SearchOptions struct {
+	//Regexp treats the pattern as a regular expression
+	//instead of plain text.
+	Regexp bool
+	Case   CaseMode
+}
+
+//match is the byte range of a match in a line of content.
+type match struct {
+	line       int
+	start, end int
+}
+
+//search holds the matches of the pattern being searched for in a Label.
+type search struct {
+	re      *regexp.Regexp
+	matches []match
+	//current is the index of the match last jumped to, -1 if none
+	current int
+	//searched is the number of lines of content searched so far
+	searched int
+
+	style, currentStyle Style
+}
+
+func newSearch(re *regexp.Regexp) *search {
+	return &search{
+		re:           re,
+		current:      -1,
+		style:        Style{Fg: termbox.AttrReverse},
+		currentStyle: Style{Fg: termbox.ColorBlack, Bg: termbox.ColorYellow},
+	}
+}
+
+//reset forgets the matches found once the content is cleared.
+func (s *search) reset() {
+	s.matches, s.current, s.searched = nil, -1, 0
+}
+
+//compileSearch turns the pattern into a regexp according to opts.
+func compileSearch(pattern string, opts SearchOptions) (*regexp.Regexp, error) {
+	ignore := opts.Case == IgnoreCase
+	if opts.Case == SmartCase {
+		ignore = true
+		for _, ch := range pattern {
+			if unicode.IsUpper(ch) {
+				ignore = false
+				break
+			}
+		}
+	}
+	if !opts.Regexp {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if ignore {
+		pattern = "(?i)" + pattern
+	}
+	return regexp.Compile(pattern)
+}
+
+//update searches the lines added to content since the last update after
+//removing the matches of the dropped lines.
+func (s *search) update(content *lineBuffer, dropped int) {
+	if dropped > 0 {
+		i := sort.Search(len(s.matches), func(i int) bool {
+			return s.matches[i].line >= dropped
+		})
+		s.matches = append(s.matches[:0], s.matches[i:]...)
+		for j := range s.matches {
+			s.matches[j].line -= dropped
+		}
+		if s.current -= i; s.current < 0 {
+			s.current = -1
+		}
+		if s.searched -= dropped; s.searched < 0 {
+			s.searched = 0
+		}
+	}
+	for ; s.searched < content.len(); s.searched++ {
+		text := content.at(s.searched).text
+		for _, loc := range s.re.FindAllStringIndex(text, -1) {
+			//empty matches can not be highlighted
+			if loc[0] < loc[1] {
+				s.matches = append(s.matches, match{line: s.searched, start: loc[0], end: loc[1]})
+			}
+		}
+	}
+}
+
+//splice updates the matches once the n lines from the i'th line
+//have been replaced with m lines.
+func (s *search) splice(content *lineBuffer, i, n, m int) {
+	if s.searched <= i {
+		//the lines have not been searched yet
+		return
+	}
+	first := sort.Search(len(s.matches), func(j int) bool {
+		return s.matches[j].line >= i
+	})
+	last := sort.Search(len(s.matches), func(j int) bool {
+		return s.matches[j].line >= i+n
+	})
+	var found []match
+	for j := i; j < i+m; j++ {
+		for _, loc := range s.re.FindAllStringIndex(content.at(j).text, -1) {
+			if loc[0] < loc[1] {
+				found = append(found, match{line: j, start: loc[0], end: loc[1]})
+			}
+		}
+	}
+	tail := s.matches[last:]
+	for k := range tail {
+		tail[k].line += m - n
+	}
+	matches := append(append(append([]match(nil), s.matches[:first]...), found...), tail...)
+
+	switch {
+	case s.current >= last:
+		s.current += len(found) - (last - first)
+	case s.current >= first:
+		s.current = -1
+	}
+	if s.searched >= i+n {
+		s.searched += m - n
+	} else {
+		s.searched = i + m
+	}
+	s.matches = matches
+}
+
+//lineMatches returns the matches in the i'th line along with the
+//index of the first of them.
+func (s *search) lineMatches(i int) ([]match, int) {
+	first := sort.Search(len(s.matches), func(j int) bool {
+		return s.matches[j].line >= i
+	})
+	last := first
+	for last < len(s.matches) && s.matches[last].line == i {
+		last++
+	}
+	return s.matches[first:last], first
+}
+
+//Search highlights every match of pattern in the text of the label and
+//scrolls to the first match at or after the top of the view.
+//Text written later is searched as well.
+//An empty pattern clears the search.
+//It returns an error if pattern is not a valid regular expression.
+func (lbl *Label) Search(pattern string, opts SearchOptions) error {
+	if pattern == "" {
+		lbl.ClearSearch()
+		return nil
+	}
+	re, err := compileSearch(pattern, opts)
+	if err != nil {
+		return err
+	}
+	old := lbl.search
+	lbl.search = newSearch(re)
+	if old != nil {
+		lbl.search.style, lbl.search.currentStyle = old.style, old.currentStyle
+	}
+	lbl.format()
+	lbl.search.update(&lbl.content, 0)
+
+	matches := lbl.search.matches
+	i := sort.Search(len(matches), func(i int) bool {
+		m := matches[i]
+		return m.line > lbl.startLine || m.line == lbl.startLine && lbl.rowOf(m) >= lbl.startRow
+	})
+	if i == len(matches) {
+		i = 0
+	}
+	lbl.jumpTo(i)
+	return nil
+}
+
+//ClearSearch removes the highlighting of the last search.
+func (lbl *Label) ClearSearch() {
+	if lbl.search != nil {
+		lbl.search.re = nil
+		lbl.search.reset()
+	}
+}
+
+//SetMatchStyle sets the styles drawn over the matches of a search and
+//over the match last jumped to. By default the matches are reversed.
+func (lbl *Label) SetMatchStyle(match, current Style) {
+	if lbl.search == nil {
+		lbl.search = newSearch(nil)
+	}
+	lbl.search.style, lbl.search.currentStyle = match, current
+}
+
+//MatchCount returns the number of matches of the search.
+func (lbl *Label) MatchCount() int {
+	if !lbl.searching() {
+		return 0
+	}
+	lbl.format()
+	return len(lbl.search.matches)
+}
+
+//CurrentMatch returns the index of the match last jumped to,
+//or -1 if there is none.
+func (lbl *Label) CurrentMatch() int {
+	if !lbl.searching() {
+		return -1
+	}
+	lbl.format()
+	return lbl.search.current
+}
+
+//NextMatch scrolls to the match after the current one,
+//going back to the first match after the last one.
+//It returns ErrNoMatch if nothing matches the search.
+func (lbl *Label) NextMatch() error {
+	return lbl.stepMatch(1)
+}
+
+//PrevMatch scrolls to the match before the current one,
+//going to the last match before the first one.
+//It returns ErrNoMatch if nothing matches the search.
+func (lbl *Label) PrevMatch() error {
+	return lbl.stepMatch(-1)
+}
+
+func (lbl *Label) stepMatch(dir int) error {
+	if !lbl.searching() {
+		return ErrNoMatch
+	}
+	lbl.format()
+	n := len(lbl.search.matches)
+	if n == 0 {
+		return ErrNoMatch
+	}
+	i := lbl.search.current + dir
+	if lbl.search.current < 0 && dir < 0 {
+		i = n - 1
+	}
+	lbl.jumpTo((i + n) % n)
+	return nil
+}
+
+func (lbl *Label) searching() bool {
+	return lbl.search != nil && lbl.search.re != nil
+}
+
+//rowOf returns the row of its line the match starts on.
+func (lbl *Label) rowOf(m match) int {
+	rows := lbl.rows(m.line)
+	for r := len(rows) - 1; r > 0; r-- {
+		if rows[r].start <= m.start {
+			return r
+		}
+	}
+	return 0
+}
+
+//jumpTo makes the i'th match the current one and scrolls it
+//to the top of the view unless it is already in view.
+//Lines that are not wrapped are scrolled horizontally as well.
+func (lbl *Label) jumpTo(i int) {
+	if i >= len(lbl.search.matches) {
+		lbl.search.current = -1
+		return
+	}
+	lbl.search.current = i
+	m := lbl.search.matches[i]
+	r := lbl.rowOf(m)
+	if len(lbl.rows(m.line)) == 0 {
+		return
+	}
+	visible := false
+	lbl.visibleRows(func(y, line, j int, _ row) bool {
+		visible = line == m.line && j == r
+		return !visible
+	})
+	if !visible {
+		lbl.startLine, lbl.startRow = m.line, r
+		lbl.clampEnd()
+	}
+	if lbl.wrap == WrapNone {
+		text := lbl.content.at(m.line).text
+		col := textWidthAt(text[:m.start], 0, lbl.tabWidth)
+		end := textWidthAt(text[:m.end], 0, lbl.tabWidth)
+		if col < lbl.hscroll || end > lbl.hscroll+lbl.viewWidth {
+			if lbl.hscroll = col - lbl.viewWidth/2; lbl.hscroll < 0 {
+				lbl.hscroll = 0
+			}
+		}
+	}
+	lbl.following = lbl.atEnd()
+}
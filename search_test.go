@@ -0,0 +1,53 @@
+package termboxui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLabelMatchesAcrossRows(t *testing.T) {
+	lbl := NewLabel()
+	lbl.Resize(10, 2)
+	lbl.SetText(strings.Join([]string{
+		"one",
+		"two",
+		"x needle y needle",
+		"three",
+		"four",
+		"needle",
+	}, "\n"))
+	if err := lbl.Search("needle", SearchOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := lbl.MatchCount(); got != 3 {
+		t.Fatalf("got %d matches, want 3", got)
+	}
+
+	//the view is scrolled to the row of a match unless it is in view
+	steps := []struct {
+		name       string
+		step       func() error
+		current    int
+		line, row  int
+	}{
+		{"NextMatch on the next row", lbl.NextMatch, 1, 2, 0},
+		{"NextMatch on the last line", lbl.NextMatch, 2, 4, 0},
+		{"NextMatch back to the first", lbl.NextMatch, 0, 2, 0},
+		{"PrevMatch back to the last", lbl.PrevMatch, 2, 4, 0},
+		{"PrevMatch on the second row", lbl.PrevMatch, 1, 2, 1},
+	}
+	if lbl.startLine != 2 || lbl.startRow != 0 {
+		t.Errorf("Search: got %d:%d at the top, want 2:0", lbl.startLine, lbl.startRow)
+	}
+	for _, s := range steps {
+		if err := s.step(); err != nil {
+			t.Fatalf("%s: %v", s.name, err)
+		}
+		if got := lbl.CurrentMatch(); got != s.current {
+			t.Errorf("%s: got match %d, want %d", s.name, got, s.current)
+		}
+		if lbl.startLine != s.line || lbl.startRow != s.row {
+			t.Errorf("%s: got %d:%d at the top, want %d:%d", s.name, lbl.startLine, lbl.startRow, s.line, s.row)
+		}
+	}
+}
@@ -0,0 +1,339 @@
+package termboxui
+
+import (
+	"strings"
+
+	"github.com/nsf/termbox-go"
+)
+
+//TextPos is a position in the text of a Label: the byte Offset
+//in the Line'th line written to the label.
+type TextPos struct {
+	Line, Offset int
+}
+
+func (p TextPos) before(q TextPos) bool {
+	return p.Line < q.Line || p.Line == q.Line && p.Offset < q.Offset
+}
+
+//selection is the text selected between anchor, where the selection
+//was started, and head, which is moved as it is extended.
+type selection struct {
+	anchor, head TextPos
+	//dragging is set while the mouse button is held down
+	dragging bool
+}
+
+//span returns the ends of the selection in order.
+func (s *selection) span() (from, to TextPos) {
+	if s.head.before(s.anchor) {
+		return s.head, s.anchor
+	}
+	return s.anchor, s.head
+}
+
+//drop moves the selection to follow the lines of content
+//as the first dropped lines are removed.
+func (s *selection) drop(dropped int) bool {
+	for _, p := range []*TextPos{&s.anchor, &s.head} {
+		if p.Line -= dropped; p.Line < 0 {
+			*p = TextPos{}
+		}
+	}
+	return s.anchor != s.head
+}
+
+//splice moves the selection to follow the lines of content once the
+//n lines from the i'th line have been replaced with m lines.
+//It returns false if the selection was within the lines replaced.
+func (s *selection) splice(i, n, m int) bool {
+	for _, p := range []*TextPos{&s.anchor, &s.head} {
+		if p.Line >= i+n {
+			p.Line += m - n
+		} else if p.Line >= i {
+			return false
+		}
+	}
+	return true
+}
+
+//drawnRow records where the text of a row was drawn so that
+//positions on the screen can be turned back into TextPos.
+type drawnRow struct {
+	//line is the index of the line of the row, -1 for no row
+	line int
+	//end is the offset of the end of the row
+	end   int
+	cells []drawnCell
+}
+
+//drawnCell is a cluster of the text drawn width cells wide from the
+//column col of the view, covering the bytes from start to end.
+type drawnCell struct {
+	col, width int
+	start, end int
+}
+
+//selected returns whether the byte at offset pos of the i'th line is selected.
+func (lbl *Label) selected(i, pos int) bool {
+	if lbl.sel == nil {
+		return false
+	}
+	from, to := lbl.sel.span()
+	p := TextPos{i, pos}
+	return !p.before(from) && p.before(to)
+}
+
+//Select selects the text from one position up to another.
+func (lbl *Label) Select(from, to TextPos) {
+	lbl.sel = &selection{anchor: lbl.clampPos(from), head: lbl.clampPos(to)}
+}
+
+//SelectAll selects all of the text.
+func (lbl *Label) SelectAll() {
+	n := lbl.content.len()
+	if n == 0 {
+		lbl.sel = nil
+		return
+	}
+	lbl.Select(TextPos{}, TextPos{n - 1, len(lbl.content.at(n - 1).text)})
+}
+
+//ClearSelection removes the selection.
+func (lbl *Label) ClearSelection() {
+	lbl.sel = nil
+}
+
+//Selection returns the ends of the selection in order.
+//ok is false if no text is selected.
+func (lbl *Label) Selection() (from, to TextPos, ok bool) {
+	if lbl.sel == nil {
+		return TextPos{}, TextPos{}, false
+	}
+	from, to = lbl.sel.span()
+	return from, to, from != to
+}
+
+//SelectedText returns the selected text as it was written, joining
+//the lines with newlines. Lines wrapped over several rows are not broken
+//up and lines hidden by the filter are left out.
+func (lbl *Label) SelectedText() string {
+	from, to, ok := lbl.Selection()
+	if !ok {
+		return ""
+	}
+	var lines []string
+	for i := from.Line; i <= to.Line && i < lbl.content.len(); i++ {
+		if lbl.filter != nil && !lbl.filter.shown(i) {
+			continue
+		}
+		text := lbl.content.at(i).text
+		start, end := 0, len(text)
+		if i == from.Line {
+			start = from.Offset
+		}
+		if i == to.Line {
+			end = to.Offset
+		}
+		lines = append(lines, text[start:end])
+	}
+	return strings.Join(lines, "\n")
+}
+
+//SetClipboard sets where Copy copies the selected text to.
+//A nil clipboard uses DefaultClipboard.
+func (lbl *Label) SetClipboard(c Clipboard) {
+	lbl.clipboard = c
+}
+
+//Copy copies the selected text to the clipboard.
+//It returns ErrNoClipboard if neither the label nor DefaultClipboard
+//has a clipboard set.
+func (lbl *Label) Copy() error {
+	text := lbl.SelectedText()
+	if text == "" {
+		return nil
+	}
+	c := lbl.clipboard
+	if c == nil {
+		c = DefaultClipboard
+	}
+	if c == nil {
+		return ErrNoClipboard
+	}
+	return c.SetText(text)
+}
+
+//clampPos moves p onto the start of a cluster of the text.
+func (lbl *Label) clampPos(p TextPos) TextPos {
+	n := lbl.content.len()
+	if n == 0 || p.Line < 0 {
+		return TextPos{}
+	}
+	if p.Line >= n {
+		return TextPos{n - 1, len(lbl.content.at(n - 1).text)}
+	}
+	text := lbl.content.at(p.Line).text
+	if p.Offset >= len(text) {
+		return TextPos{p.Line, len(text)}
+	}
+	pos := 0
+	for pos < p.Offset {
+		size, _ := clusterAt(text[pos:])
+		if pos+size > p.Offset {
+			break
+		}
+		pos += size
+	}
+	return TextPos{p.Line, pos}
+}
+
+//PosAt returns the position of the text drawn at the screen
+//coordinates x, y the last time the label was drawn. A point past
+//the end of a row or below the text is at the end of the row above it.
+func (lbl *Label) PosAt(x, y int) (TextPos, bool) {
+	vx, vy := lbl.viewOrigin()
+	x -= vx + lbl.gutter
+	y -= vy
+	if y >= len(lbl.drawn) {
+		y = len(lbl.drawn) - 1
+	}
+	if y < 0 {
+		y = 0
+	}
+	for ; y >= 0; y-- {
+		if y >= len(lbl.drawn) || lbl.drawn[y].line < 0 {
+			x = lbl.viewWidth
+			continue
+		}
+		d := lbl.drawn[y]
+		p := TextPos{Line: d.line, Offset: d.end}
+		for _, c := range d.cells {
+			if x < c.col+c.width {
+				p.Offset = c.start
+				break
+			}
+		}
+		return p, true
+	}
+	return TextPos{}, false
+}
+
+//MouseSelect selects text with the left mouse button, extending the
+//selection as the mouse is dragged. It returns whether the event was
+//used. Mouse events are reported once termbox.InputMouse is set
+//with termbox.SetInputMode.
+func (lbl *Label) MouseSelect(ev termbox.Event) bool {
+	if ev.Type != termbox.EventMouse {
+		return false
+	}
+	inside := ev.MouseX >= lbl.x && ev.MouseX < lbl.x+lbl.width &&
+		ev.MouseY >= lbl.y && ev.MouseY < lbl.y+lbl.height
+	switch ev.Key {
+	case termbox.MouseLeft:
+		if lbl.sel != nil && lbl.sel.dragging && ev.Mod&termbox.ModMotion != 0 {
+			if p, ok := lbl.PosAt(ev.MouseX, ev.MouseY); ok {
+				lbl.sel.head = p
+			}
+			return true
+		}
+		if !inside {
+			return false
+		}
+		p, ok := lbl.PosAt(ev.MouseX, ev.MouseY)
+		if !ok {
+			lbl.sel = nil
+			return true
+		}
+		lbl.sel = &selection{anchor: p, head: p, dragging: true}
+		return true
+	case termbox.MouseRelease:
+		if lbl.sel != nil && lbl.sel.dragging {
+			lbl.sel.dragging = false
+			if p, ok := lbl.PosAt(ev.MouseX, ev.MouseY); ok {
+				lbl.sel.head = p
+			}
+			return true
+		}
+	}
+	return false
+}
+
+//ExtendSelection moves the end of the selection by rows rows and by cells
+//clusters of text, scrolling to keep it in view. Moving past the end of
+//a line moves onto the next line. If nothing is selected the selection
+//is started at the top of the view.
+func (lbl *Label) ExtendSelection(rows, cells int) {
+	lbl.format()
+	if lbl.content.len() == 0 {
+		return
+	}
+	if lbl.sel == nil {
+		start := TextPos{Line: lbl.startLine}
+		if rs := lbl.rows(lbl.startLine); lbl.startRow < len(rs) {
+			start.Offset = rs[lbl.startRow].start
+		}
+		lbl.sel = &selection{anchor: start, head: start}
+	}
+	head := lbl.sel.head
+
+	for ; cells > 0; cells-- {
+		text := lbl.content.at(head.Line).text
+		if head.Offset < len(text) {
+			size, _ := clusterAt(text[head.Offset:])
+			head.Offset += size
+		} else if next, ok := lbl.nextLine(head.Line, 1); ok {
+			head = TextPos{Line: next}
+		}
+	}
+	for ; cells < 0; cells++ {
+		if head.Offset > 0 {
+			head = lbl.clampPos(TextPos{head.Line, head.Offset - 1})
+		} else if prev, ok := lbl.nextLine(head.Line, -1); ok {
+			head = TextPos{prev, len(lbl.content.at(prev).text)}
+		}
+	}
+
+	if len(lbl.rows(head.Line)) == 0 {
+		lbl.sel.head = head
+		return
+	}
+	r := lbl.rowOf(match{line: head.Line, start: head.Offset})
+	if rows != 0 {
+		text := lbl.content.at(head.Line).text
+		col := textWidthAt(text[lbl.rows(head.Line)[r].start:head.Offset], 0, lbl.tabWidth)
+		line, nr, _ := lbl.moveRows(head.Line, r, rows)
+		row := lbl.rows(line)[nr]
+		text = lbl.content.at(line).text
+		pos, at := row.start, 0
+		for pos < row.end {
+			size, width := cellWidth(text[pos:row.end], at, lbl.tabWidth)
+			if at+width > col {
+				break
+			}
+			at += width
+			pos += size
+		}
+		head, r = TextPos{line, pos}, nr
+	}
+	lbl.sel.head = head
+	lbl.scrollToRow(head.Line, r)
+}
+
+//scrollToRow scrolls as little as needed to bring the r'th row
+//of the line into view.
+func (lbl *Label) scrollToRow(line, r int) {
+	if line < lbl.startLine || line == lbl.startLine && r < lbl.startRow {
+		lbl.startLine, lbl.startRow = line, r
+	} else {
+		visible := false
+		lbl.visibleRows(func(y, l, i int, _ row) bool {
+			visible = l == line && i == r
+			return !visible
+		})
+		if !visible {
+			lbl.startLine, lbl.startRow, _ = lbl.moveRows(line, r, 1-lbl.viewHeight)
+		}
+	}
+	lbl.following = lbl.atEnd()
+}
@@ -0,0 +1,48 @@
+package termboxui
+
+import "testing"
+
+func TestLabelSelectedText(t *testing.T) {
+	lbl := NewLabel()
+	lbl.Resize(4, 5)
+	lbl.SetText("hello\nwide world\nagain")
+	tests := []struct {
+		name     string
+		from, to TextPos
+		want     string
+	}{
+		{"one line", TextPos{0, 1}, TextPos{0, 4}, "ell"},
+		{"across lines", TextPos{0, 2}, TextPos{2, 3}, "llo\nwide world\naga"},
+		{"backwards", TextPos{2, 3}, TextPos{0, 2}, "llo\nwide world\naga"},
+		{"to the end of a line", TextPos{0, 3}, TextPos{1, 0}, "lo\n"},
+		{"past the end", TextPos{1, 5}, TextPos{9, 0}, "world\nagain"},
+		{"empty", TextPos{1, 2}, TextPos{1, 2}, ""},
+	}
+	for _, tt := range tests {
+		lbl.Select(tt.from, tt.to)
+		if got := lbl.SelectedText(); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	lbl.SelectAll()
+	lbl.SetFilterPattern("l", SearchOptions{}, 0)
+	if got, want := lbl.SelectedText(), "hello\nwide world"; got != want {
+		t.Errorf("filtered: got %q, want %q", got, want)
+	}
+}
+
+func TestLabelExtendSelection(t *testing.T) {
+	lbl := NewLabel()
+	lbl.Resize(10, 5)
+	lbl.SetText("ab\ncd")
+	lbl.ExtendSelection(0, 3)
+	if got := lbl.SelectedText(); got != "ab\n" {
+		t.Errorf("onto the next line: got %q, want %q", got, "ab\n")
+	}
+	lbl.ExtendSelection(0, 1)
+	lbl.ExtendSelection(0, -2)
+	if got := lbl.SelectedText(); got != "ab" {
+		t.Errorf("back onto the first line: got %q, want %q", got, "ab")
+	}
+}
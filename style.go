@@ -0,0 +1,263 @@
+package termboxui
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/nsf/termbox-go"
+)
+
+//attrMask covers every termbox attribute that is not a color.
+const attrMask = termbox.AttrBold | termbox.AttrBlink | termbox.AttrHidden |
+	termbox.AttrDim | termbox.AttrUnderline | termbox.AttrCursive | termbox.AttrReverse
+
+//Style describes how text is drawn.
+//Attributes such as termbox.AttrBold, termbox.AttrUnderline and
+//termbox.AttrReverse are combined with Fg the same way termbox does.
+//A ColorDefault color is replaced by the color of the window drawing the text.
+type Style struct {
+	Fg, Bg termbox.Attribute
+}
+
+//over returns the style with its default colors taken from base.
+//The attributes of both styles are combined.
+func (s Style) over(base Style) Style {
+	fg, bg := s.Fg, s.Bg
+	if fg&^attrMask == termbox.ColorDefault {
+		fg |= base.Fg &^ attrMask
+	}
+	fg |= base.Fg & attrMask
+	if bg&^attrMask == termbox.ColorDefault {
+		bg |= base.Bg
+	}
+	return Style{Fg: fg, Bg: bg}
+}
+
+//Span is a run of text drawn with a single style.
+type Span struct {
+	Text  string
+	Style Style
+}
+
+//styleRun sets the style of a line starting from the byte offset start
+//until the start of the next run.
+type styleRun struct {
+	start int
+	style Style
+}
+
+//styledLine is a line of text along with the styles of its runes.
+type styledLine struct {
+	text  string
+	runs  []styleRun
+	align Align
+	//sign is drawn in the gutter next to the line, if any
+	sign *Sign
+	//prefix is drawn at the start of the rows the line is wrapped onto
+	//after the first in place of the continuation marker, if set
+	prefix *Span
+	//highlit are the runs set by the highlighter of the Label
+	//and state is the state of the highlighter at the end of the line
+	highlit []styleRun
+	state   int
+
+	//rows caches the rows the line is wrapped onto.
+	//They are valid while wrapped is set and layout matches the Label.
+	rows    []row
+	wrapped bool
+	layout  int
+}
+
+//styleAt returns the style of the byte at offset i.
+//run is a hint of the run to start searching from and the
+//index of the run found is returned along with the style.
+func (l *styledLine) styleAt(i, run int) (Style, int) {
+	if run >= len(l.runs) || l.runs[run].start > i {
+		run = 0
+	}
+	for run+1 < len(l.runs) && l.runs[run+1].start <= i {
+		run++
+	}
+	if run >= len(l.runs) || l.runs[run].start > i {
+		return Style{}, run
+	}
+	return l.runs[run].style, run
+}
+
+//add appends text drawn with style to the line.
+func (l *styledLine) add(text string, style Style) {
+	if text == "" {
+		return
+	}
+	if n := len(l.runs); n == 0 || l.runs[n-1].style != style {
+		l.runs = append(l.runs, styleRun{start: len(l.text), style: style})
+	}
+	l.text += text
+}
+
+//spansToLines splits the spans on newlines into styled lines.
+//The carriage return of a CRLF line ending is removed.
+func spansToLines(spans []Span) []styledLine {
+	lines := []styledLine{{}}
+	for _, span := range spans {
+		parts := strings.Split(span.Text, "\n")
+		for i, part := range parts {
+			if i > 0 {
+				lines = append(lines, styledLine{})
+			}
+			lines[len(lines)-1].add(part, span.Style)
+		}
+	}
+	for i := range lines[:len(lines)-1] {
+		lines[i].text = strings.TrimSuffix(lines[i].text, "\r")
+	}
+	return lines
+}
+
+var colorNames = map[string]termbox.Attribute{
+	"default":      termbox.ColorDefault,
+	"black":        termbox.ColorBlack,
+	"red":          termbox.ColorRed,
+	"green":        termbox.ColorGreen,
+	"yellow":       termbox.ColorYellow,
+	"blue":         termbox.ColorBlue,
+	"magenta":      termbox.ColorMagenta,
+	"cyan":         termbox.ColorCyan,
+	"white":        termbox.ColorWhite,
+	"darkgray":     termbox.ColorDarkGray,
+	"lightred":     termbox.ColorLightRed,
+	"lightgreen":   termbox.ColorLightGreen,
+	"lightyellow":  termbox.ColorLightYellow,
+	"lightblue":    termbox.ColorLightBlue,
+	"lightmagenta": termbox.ColorLightMagenta,
+	"lightcyan":    termbox.ColorLightCyan,
+	"lightgray":    termbox.ColorLightGray,
+}
+
+var attrNames = map[byte]termbox.Attribute{
+	'b': termbox.AttrBold,
+	'd': termbox.AttrDim,
+	'i': termbox.AttrCursive,
+	'l': termbox.AttrBlink,
+	'r': termbox.AttrReverse,
+	'u': termbox.AttrUnderline,
+}
+
+//parseColor parses a color name, a number between 0 and 255 for
+//the 256 color output mode or a #rrggbb value for the RGB output mode.
+func parseColor(name string) (termbox.Attribute, bool) {
+	if c, ok := colorNames[name]; ok {
+		return c, true
+	}
+	if len(name) == 7 && name[0] == '#' {
+		rgb, err := strconv.ParseUint(name[1:], 16, 32)
+		if err != nil {
+			return 0, false
+		}
+		return termbox.RGBToAttribute(uint8(rgb>>16), uint8(rgb>>8), uint8(rgb)), true
+	}
+	n, err := strconv.ParseUint(name, 10, 8)
+	if err != nil {
+		return 0, false
+	}
+	return termbox.Attribute(n + 1), true
+}
+
+//parseTag parses a style tag of the form fg:bg:attrs without brackets
+//and applies it to cur. Each field is optional and a "-" resets it.
+//A tag consisting of only "-" resets the whole style.
+func parseTag(tag string, cur Style) (Style, bool) {
+	if tag == "-" {
+		return Style{}, true
+	}
+	fields := strings.Split(tag, ":")
+	if len(fields) > 3 {
+		return cur, false
+	}
+	style := cur
+	attrs := cur.Fg & attrMask
+	fg := cur.Fg &^ attrMask
+	for i, field := range fields {
+		switch {
+		case field == "":
+			continue
+		case i == 2 && field == "-":
+			attrs = 0
+		case i == 2:
+			var a termbox.Attribute
+			for j := 0; j < len(field); j++ {
+				attr, ok := attrNames[field[j]]
+				if !ok {
+					return cur, false
+				}
+				a |= attr
+			}
+			attrs = a
+		case field == "-" && i == 0:
+			fg = termbox.ColorDefault
+		case field == "-":
+			style.Bg = termbox.ColorDefault
+		default:
+			c, ok := parseColor(field)
+			if !ok {
+				return cur, false
+			}
+			if i == 0 {
+				fg = c
+			} else {
+				style.Bg = c
+			}
+		}
+	}
+	style.Fg = fg | attrs
+	return style, true
+}
+
+//ParseMarkup converts text containing style tags into spans.
+//A tag is written as [fg:bg:attrs] where fg and bg are color names
+//(such as red or lightblue), a 256 color number or #rrggbb and attrs
+//is any of b (bold), u (underline), r (reverse), d (dim), i (italic)
+//and l (blink). Fields may be left empty to keep their current value
+//or set to "-" to reset them, for example [red::b], [:blue] or [::-].
+//The tag [-] resets the style to the default.
+//A bracket that does not start a valid tag is kept as text and
+//[[ can be used to write a literal [.
+//The style in effect at the end of the text is returned so that it
+//can be continued in following text.
+func ParseMarkup(text string, cur Style) ([]Span, Style) {
+	var spans []Span
+	var buf strings.Builder
+	flush := func() {
+		if buf.Len() > 0 {
+			spans = append(spans, Span{Text: buf.String(), Style: cur})
+			buf.Reset()
+		}
+	}
+	for len(text) > 0 {
+		i := strings.IndexByte(text, '[')
+		if i < 0 {
+			buf.WriteString(text)
+			break
+		}
+		buf.WriteString(text[:i])
+		text = text[i:]
+		if strings.HasPrefix(text, "[[") {
+			buf.WriteByte('[')
+			text = text[2:]
+			continue
+		}
+		end := strings.IndexAny(text[1:], "[]\n")
+		if end > 0 && text[end+1] == ']' {
+			if style, ok := parseTag(text[1:end+1], cur); ok {
+				flush()
+				cur = style
+				text = text[end+2:]
+				continue
+			}
+		}
+		buf.WriteByte('[')
+		text = text[1:]
+	}
+	flush()
+	return spans, cur
+}
@@ -3,4 +3,32 @@
 //encapsulate and wrap all of its functions.
 package termboxui
 
+import "errors"
+
 //var ErrEOF = errors.New("EOF")
+
+var (
+	//ErrContainerFull is returned when placing a window
+	//into a container that has no empty slot left.
+	ErrContainerFull = errors.New("termboxui: container is full")
+	//ErrNotFound is returned when a window is not in the container.
+	ErrNotFound = errors.New("termboxui: window not found")
+	//ErrIndexOutOfRange is returned when a slot index is not
+	//valid for the container.
+	ErrIndexOutOfRange = errors.New("termboxui: index out of range")
+	//ErrNilWindow is returned when placing a nil window.
+	ErrNilWindow = errors.New("termboxui: nil window")
+	//ErrNoMatch is returned when jumping to a match of a search
+	//that matches nothing.
+	ErrNoMatch = errors.New("termboxui: no match")
+	//ErrNoClipboard is returned when copying with no clipboard to copy to.
+	ErrNoClipboard = errors.New("termboxui: no clipboard")
+
+	//SkipChildren is used as a return value from a WalkFunc to
+	//indicate that the children of the current window are to be skipped.
+	//It is not returned as an error by Walk.
+	SkipChildren = errors.New("termboxui: skip children")
+
+	//errFound stops a Walk once a lookup has found its window.
+	errFound = errors.New("termboxui: found")
+)
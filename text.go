@@ -1,95 +1,271 @@
 package termboxui
 
 import (
-	"strings"
+	"bytes"
 	"unicode"
 	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
 )
 
-// Wraps the text into multiple lines at most lim chars long.
+// Wraps the text into multiple lines at most lim cells wide.
+// Lines are broken following the Unicode Line Breaking Algorithm,
+// see DefaultBreakClass. Words too long to fit are hyphenated.
 // Returns a list of all the lines.
-// Supports unicode.
+// Supports unicode: wide characters take up two cells and
+// combining characters are kept with the character they modify.
 func WrapText(text string, lim int) []string {
+	ranges := wrapRanges(text, lim, lim, DefaultBreakClass, DefaultTabWidth)
+	lines := make([]string, 0, len(ranges))
+	for _, r := range ranges {
+		line := text[r.start:r.end]
+		if r.hyphen {
+			line += "-"
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+//textRange is the slice [start, end) of a line of text.
+//hyphen is set when a word was broken at the end of the range.
+type textRange struct {
+	start, end int
+	hyphen     bool
+}
+
+//wrapRanges wraps the text the same way as WrapText but returns the
+//location of each line within text instead of copying it.
+//The first line is wrapped at first cells and the following ones at rest.
+//classOf returns the break class of each character and tab is the
+//distance between tab stops.
+func wrapRanges(text string, first, rest int, classOf func(rune) BreakClass, tab int) []textRange {
 	slice := []byte(text)
-	lines := make([]string, 0, 2)
+	ranges := make([]textRange, 0, 2)
+	lim := first
+	push := func(r textRange) {
+		ranges = append(ranges, r)
+		lim = rest
+	}
 
-	curLine := ""
+	//the current line is text[start:end] and is width cells wide
+	start, end, width := 0, 0, 0
 
-	for len(slice) > 0 {
-		length := lenWord(slice)
-		if len(curLine)+length <= lim {
-			curLine += string(slice[:length])
-		} else if len(curLine) > 0 {
-			lines = append(lines, strings.TrimSpace(curLine))
-			curLine = string(slice[:length])
+	for i := 0; i < len(slice); {
+		//each segment is the text up to the next opportunity to break the line
+		length := nextBreak(text[i:], classOf)
+		word := len(bytes.TrimRightFunc(slice[i:i+length], unicode.IsSpace))
+		wordWidth := textWidthAt(text[i:i+word], width, tab)
+		if end > start && width+wordWidth > lim {
+			//a line of only spaces is dropped rather than left blank
+			if r := trimRange(slice, start, end); r.end > r.start {
+				push(r)
+			}
+			start, end, width = i, i, 0
+		}
+		if end == start && wordWidth > lim {
+			broken := breakRanges(text[i:i+word], lim, rest)
+			for _, r := range broken[:len(broken)-1] {
+				push(textRange{start: r.start + i, end: r.end + i, hyphen: r.hyphen})
+			}
+			start = broken[len(broken)-1].start + i
+			width = textWidthAt(text[start:i+length], 0, tab)
 		} else {
-			tmp := breakWord(string(slice[:length]), lim)
-			curLine = tmp[len(tmp)-1]
-			lines = append(lines, tmp[:len(tmp)-1]...)
+			if end == start {
+				start = i
+			}
+			width += textWidthAt(text[i:i+length], width, tab)
 		}
-		slice = slice[length:]
+		i += length
+		end = i
 	}
-	if len(curLine) > 0 {
-		lines = append(lines, strings.TrimSpace(curLine))
+	if end > start {
+		push(trimRange(slice, start, end))
 	}
 
-	return lines
+	return ranges
 }
 
-func lenWord(text []byte) int {
-	var size, length int
-	var r rune
+//charRanges wraps the text at the last character that fits on each line
+//without looking for word boundaries or trimming spaces.
+//The first line is wrapped at first cells and the following ones at rest.
+func charRanges(text string, first, rest, tab int) []textRange {
+	var ranges []textRange
+	lim := first
+	start, width := 0, 0
+	for i := 0; i < len(text); {
+		size, w := cellWidth(text[i:], width, tab)
+		if i > start && width+w > lim {
+			ranges = append(ranges, textRange{start: start, end: i})
+			start, width, lim = i, 0, rest
+		}
+		width += w
+		i += size
+	}
+	if start < len(text) {
+		ranges = append(ranges, textRange{start: start, end: len(text)})
+	}
+	return ranges
+}
 
-	for i := 0; i < len(text); i += size {
-		r, size = utf8.DecodeRune(text[i:])
-		if unicode.IsSpace(r) {
-			return length + size
+//trimRange returns the range [start, end) without surrounding spaces.
+func trimRange(text []byte, start, end int) textRange {
+	for start < end {
+		r, size := utf8.DecodeRune(text[start:end])
+		if !unicode.IsSpace(r) {
+			break
 		}
-		length += size
+		start += size
 	}
-	return length
+	for end > start {
+		r, size := utf8.DecodeLastRune(text[start:end])
+		if !unicode.IsSpace(r) {
+			break
+		}
+		end -= size
+	}
+	return textRange{start: start, end: end}
 }
 
-func wordAt(text []byte, at int) (start, end int) {
-	start, end = -1, -1
+//breakRanges breaks the word into ranges that fit once a hyphen is
+//added to all but the last range. The first range fits in first cells
+//and the following ones in rest.
+//Characters are never split, so a range may still overflow
+//if a single character is wider than the room available.
+func breakRanges(word string, first, rest int) []textRange {
+	var ranges []textRange
+	lim := first
 
-	// Iterate backwords to find the start of the word
-	for i := at - 1; i > 0; i-- {
-		if !utf8.RuneStart(text[i]) {
-			continue
+	start := 0
+	for textWidth(word[start:]) > lim {
+		step := lim - 1
+		hyphen := true
+		if step < 1 {
+			//no room for a hyphen
+			step, hyphen = 1, false
 		}
-		r, size := utf8.DecodeRune(text[i:])
-		if unicode.IsSpace(r) {
-			start = i + size
+		end, width := start, 0
+		for end < len(word) {
+			size, w := clusterAt(word[end:])
+			if end > start && width+w > step {
+				break
+			}
+			end += size
+			width += w
 		}
+		ranges = append(ranges, textRange{start: start, end: end, hyphen: hyphen})
+		start, lim = end, rest
+	}
+	if start < len(word) {
+		ranges = append(ranges, textRange{start: start, end: len(word)})
 	}
+	return ranges
+}
+
+//DefaultTabWidth is the distance between tab stops used by WrapText
+//and by the windows that have no tab width set.
+const DefaultTabWidth = 8
 
-	//Iterate forwards to find end of word
-	r, size := utf8.DecodeRune(text[at:])
-	last := at
+//textWidth returns the number of cells needed to display text
+//with the default tab stops.
+func textWidth(text string) int {
+	return textWidthAt(text, 0, DefaultTabWidth)
+}
 
-	for i := at + size; i < len(text); i += size {
-		r, size = utf8.DecodeRune(text[i:])
-		if unicode.IsSpace(r) {
-			end = last
+//textWidthAt returns the number of cells needed to display text
+//starting on the col'th column with tab stops every tab columns.
+func textWidthAt(text string, col, tab int) int {
+	width := 0
+	for len(text) > 0 {
+		size, w := cellWidth(text, col+width, tab)
+		width += w
+		text = text[size:]
+	}
+	return width
+}
+
+//cellWidth is the same as clusterAt except that a tab
+//extends from the col'th column to the next of the stops every tab columns.
+func cellWidth(text string, col, tab int) (size, width int) {
+	if len(text) > 0 && text[0] == '\t' {
+		if tab < 1 {
+			tab = 1
 		}
-		last = i + size
+		return 1, tab - col%tab
 	}
+	return clusterAt(text)
+}
 
-	return
+//isControl returns whether r is a control character other than tab.
+//Control characters are displayed in caret notation, such as ^M.
+func isControl(r rune) bool {
+	return r < ' ' && r != '\t' || r == 0x7f
 }
 
-// Returns a string representing the word broken onto multiple lines
-// and the number of characters on the last line
-func breakWord(word string, lim int) []string {
-	var lines []string
+//caret returns the character following ^ in the caret notation of r.
+func caret(r rune) rune {
+	return r ^ 0x40
+}
 
-	for len(word) > lim {
-		lines = append(lines, word[:lim-1]+"-")
-		word = word[lim-1:]
+//clusterAt returns the size in bytes of the character at the start of text,
+//including any combining characters and joined emoji following it,
+//and the number of cells it takes up on the screen.
+//A tab is counted as a single cell, see cellWidth.
+func clusterAt(text string) (size, width int) {
+	r, size := utf8.DecodeRuneInString(text)
+	if size == 0 {
+		return 0, 0
 	}
-	if len(word) > 0 {
-		lines = append(lines, word)
+	if isControl(r) {
+		return size, 2
 	}
-	return lines
+	width = runewidth.RuneWidth(r)
+	if width == 0 && r >= ' ' {
+		//a lone combining character
+		width = 1
+	}
+
+	//a pair of regional indicators is a flag
+	if isRegionalIndicator(r) {
+		if next, n := utf8.DecodeRuneInString(text[size:]); isRegionalIndicator(next) {
+			return size + n, 2
+		}
+	}
+
+	for size < len(text) {
+		next, n := utf8.DecodeRuneInString(text[size:])
+		switch {
+		case next == zeroWidthJoiner:
+			//the joiner and the character following it are one emoji
+			size += n
+			if size < len(text) {
+				_, n = utf8.DecodeRuneInString(text[size:])
+				size += n
+			}
+		case next == emojiPresentation:
+			size += n
+			width = 2
+		case isCombining(next):
+			size += n
+		default:
+			return size, width
+		}
+	}
+	return size, width
+}
+
+const (
+	zeroWidthJoiner   = '\u200d'
+	emojiPresentation = '\ufe0f'
+)
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
+
+//isCombining returns whether r modifies the character before it.
+func isCombining(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me) ||
+		r >= 0xfe00 && r <= 0xfe0f || //variation selectors
+		r >= 0x1f3fb && r <= 0x1f3ff //emoji skin tones
 }
@@ -0,0 +1,66 @@
+package termboxui
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestTextWidthAt(t *testing.T) {
+	tests := []struct {
+		text     string
+		col, tab int
+		want     int
+	}{
+		{"a\tb", 0, 8, 9},
+		{"a\tb", 0, 4, 5},
+		{"a\tb", 2, 4, 3},
+		{"\t\t", 0, 1, 2},
+		{"日本\t", 0, 8, 8},
+	}
+	for _, tt := range tests {
+		if got := textWidthAt(tt.text, tt.col, tt.tab); got != tt.want {
+			t.Errorf("textWidthAt(%q, %d, %d) = %d, want %d", tt.text, tt.col, tt.tab, got, tt.want)
+		}
+	}
+}
+
+func TestLabelSetTabWidth(t *testing.T) {
+	lbl := NewLabel()
+	lbl.Resize(6, 5)
+	lbl.SetText("a\tb")
+	lbl.format()
+	if got := len(lbl.rows(0)); got != 2 {
+		t.Errorf("default: got %d rows, want 2", got)
+	}
+
+	lbl.SetTabWidth(4)
+	lbl.format()
+	if got := len(lbl.rows(0)); got != 1 {
+		t.Errorf("tab width 4: got %d rows, want 1", got)
+	}
+}
+
+func TestPagerSetTabWidth(t *testing.T) {
+	text := "a\tb\n"
+	p := NewPager(strings.NewReader(text), int64(len(text)))
+	defer p.Close()
+	for p.Loading() {
+		runtime.Gosched()
+	}
+	p.Resize(2, 1)
+	tests := []struct {
+		tab  int
+		want int
+	}{
+		{8, 7},
+		{4, 3},
+	}
+	for _, tt := range tests {
+		p.SetTabWidth(tt.tab)
+		p.ScrollHorizontal(100)
+		if offset, _, _ := p.HScrollState(); offset != tt.want {
+			t.Errorf("tab width %d: scrolled to %d, want %d", tt.tab, offset, tt.want)
+		}
+	}
+}
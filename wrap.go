@@ -0,0 +1,177 @@
+package termboxui
+
+import (
+	"strings"
+	"unicode"
+)
+
+//WrapMode sets how a Label breaks lines that do not fit in its width.
+type WrapMode int
+
+const (
+	//WrapWord breaks lines between words, see WrapText.
+	WrapWord WrapMode = iota
+	//WrapChar breaks lines after the last character that fits.
+	WrapChar
+	//WrapNone does not break lines. Use ScrollHorizontal to
+	//see the text past the right edge.
+	WrapNone
+)
+
+//rows returns the rows the i'th line of content is wrapped onto.
+//The rows of each line are kept until the line or the layout changes
+//so only the lines being drawn or scrolled over are wrapped.
+//Lines hidden by the filter have no rows.
+func (lbl *Label) rows(i int) []row {
+	if lbl.filter != nil && !lbl.filter.shown(i) {
+		return nil
+	}
+	line := lbl.content.at(i)
+	if !line.wrapped || line.layout != lbl.layout {
+		line.rows = lbl.wrapLine(line)
+		line.wrapped, line.layout = true, lbl.layout
+	}
+	return line.rows
+}
+
+//relayout discards the rows of every line so that they are
+//wrapped again when they are next needed.
+//The offset of the text at the top of the view is kept so that
+//the same text stays at the top once the lines are wrapped again.
+func (lbl *Label) relayout() {
+	lbl.anchor()
+	lbl.reanchor = true
+	lbl.layout++
+	lbl.changed = true
+}
+
+//anchor records the offset of the text at the top of the view,
+//if the rows of its line are known.
+func (lbl *Label) anchor() {
+	if lbl.startLine < 0 || lbl.startLine >= lbl.content.len() {
+		return
+	}
+	line := lbl.content.at(lbl.startLine)
+	if line.wrapped && line.layout == lbl.layout && lbl.startRow < len(line.rows) {
+		lbl.startOffset = line.rows[lbl.startRow].start
+	}
+}
+
+//wrapLine breaks the line into rows that fit in the label.
+func (lbl *Label) wrapLine(line *styledLine) []row {
+	text := line.text
+	if lbl.viewWidth < 1 {
+		return nil
+	}
+	if lbl.wrap == WrapNone {
+		if len(text) == 0 {
+			return nil
+		}
+		return []row{{textRange: textRange{start: 0, end: len(text)}}}
+	}
+
+	//the leading spaces are kept on the first row
+	//and repeated on the following rows
+	lead, indent := 0, lbl.hanging
+	if lbl.keepIndent {
+		lead = len(text) - len(strings.TrimLeftFunc(text, unicode.IsSpace))
+		if lead == len(text) {
+			lead = 0
+		}
+		indent += textWidthAt(text[:lead], 0, lbl.tabWidth)
+	}
+	marker := textWidth(lbl.marker)
+	if line.prefix != nil {
+		marker = textWidth(line.prefix.Text)
+	}
+	first := lbl.viewWidth - textWidthAt(text[:lead], 0, lbl.tabWidth)
+	rest := lbl.viewWidth - marker - indent
+	if first < 1 {
+		lead, first = 0, lbl.viewWidth
+	}
+	if rest < 1 {
+		//no room left for the text
+		indent, rest = 0, lbl.viewWidth-marker
+		if rest < 1 {
+			rest = lbl.viewWidth
+		}
+	}
+
+	var ranges []textRange
+	if lbl.wrap == WrapChar {
+		ranges = charRanges(text[lead:], first, rest, lbl.tabWidth)
+	} else {
+		classOf := lbl.breakClass
+		if classOf == nil {
+			classOf = DefaultBreakClass
+		}
+		ranges = wrapRanges(text[lead:], first, rest, classOf, lbl.tabWidth)
+	}
+	rows := make([]row, len(ranges))
+	for j, r := range ranges {
+		r.start += lead
+		r.end += lead
+		if j == 0 {
+			if lead > 0 {
+				r.start = 0
+			}
+			rows[j] = row{textRange: r}
+		} else {
+			rows[j] = row{textRange: r, cont: true, indent: indent}
+		}
+	}
+	return rows
+}
+
+//SetWrapMode sets how lines too long for the label are broken.
+func (lbl *Label) SetWrapMode(mode WrapMode) {
+	lbl.wrap = mode
+	lbl.hscroll = 0
+	lbl.relayout()
+}
+
+//SetKeepIndent sets whether the leading spaces of a line are kept and
+//repeated on the rows it is wrapped onto.
+func (lbl *Label) SetKeepIndent(keep bool) {
+	lbl.keepIndent = keep
+	lbl.relayout()
+}
+
+//SetTabWidth sets the distance between tab stops, DefaultTabWidth
+//by default.
+func (lbl *Label) SetTabWidth(width int) {
+	if width < 1 {
+		width = 1
+	}
+	lbl.tabWidth = width
+	lbl.relayout()
+}
+
+//SetBreakClass sets the function returning the break class of each
+//character, tailoring where lines are wrapped in WrapWord mode.
+//For example to also break after the dots of dotted names:
+//	lbl.SetBreakClass(func(r rune) termboxui.BreakClass {
+//		if r == '.' {
+//			return termboxui.BreakBA
+//		}
+//		return termboxui.DefaultBreakClass(r)
+//	})
+//A nil function restores DefaultBreakClass.
+func (lbl *Label) SetBreakClass(classOf func(r rune) BreakClass) {
+	lbl.breakClass = classOf
+	lbl.relayout()
+}
+
+//SetHangingIndent sets the number of cells the rows following the first
+//row of a wrapped line are indented by.
+func (lbl *Label) SetHangingIndent(indent int) {
+	lbl.hanging = indent
+	lbl.relayout()
+}
+
+//SetContinuationMarker sets the text drawn at the start of the rows
+//following the first row of a wrapped line, such as "↪ ".
+func (lbl *Label) SetContinuationMarker(marker string) {
+	lbl.marker = marker
+	lbl.relayout()
+}